@@ -6,8 +6,12 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/backend/git"
+	"github.com/nobe4/action-ln/internal/backend/gitlab"
 	"github.com/nobe4/action-ln/internal/client"
 	"github.com/nobe4/action-ln/internal/client/noop"
+	"github.com/nobe4/action-ln/internal/client/offline"
 	"github.com/nobe4/action-ln/internal/environment"
 	"github.com/nobe4/action-ln/internal/github"
 	"github.com/nobe4/action-ln/internal/ln"
@@ -29,27 +33,57 @@ func main() {
 
 	e.PrintDebug()
 
+	b, err := newBackend(ctx, e)
+	if err != nil {
+		log.Error("Authentication failed", "err", err)
+		os.Exit(1)
+	}
+
+	if err := ln.Run(ctx, e, b); err != nil {
+		log.Error("Running action-ln failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+// newBackend routes the `gitlab:` repos to GitLab, and the others to the local
+// git backend for `file://` endpoints, or the authenticated GitHub API.
+func newBackend(ctx context.Context, e environment.Environment) (backend.Backend, error) {
 	var c client.Doer = &http.Client{}
-	if e.Noop {
+
+	switch {
+	case e.Offline.Dir != "":
+		c = offline.New(e.Offline.Dir, e.Offline.Journal)
+	case e.Noop:
 		c = noop.New()
 	}
 
+	gl := gitlab.New(c, e.GitLab.Endpoint)
+	gl.Token = e.GitLab.Token
+
+	if e.Local() {
+		log.Info("Using local git repositories", "root", e.Endpoint)
+
+		r := backend.NewRouter(git.New(e.Endpoint))
+		r.Add(github.ForgeGitLab, gl)
+
+		return r, nil
+	}
+
 	g := github.New(c, e.Endpoint)
 
-	if err = g.Auth(ctx,
+	if err := g.Auth(ctx,
 		e.Token,
 		e.App.ID,
 		e.App.PrivateKey,
 		e.App.InstallID,
 	); err != nil {
-		log.Error("Authentication failed", "err", err)
-		os.Exit(1)
+		return nil, err //nolint:wrapcheck // Logged by the caller.
 	}
 
-	if err := ln.Run(ctx, e, g); err != nil {
-		log.Error("Running action-ln failed", "err", err)
-		os.Exit(1)
-	}
+	r := backend.NewRouter(g)
+	r.Add(github.ForgeGitLab, gl)
+
+	return r, nil
 }
 
 //nolint:revive // debug here is expected.
@@ -0,0 +1,58 @@
+/*
+Package backend defines what action-ln needs from a forge: reading and writing
+files on branches, and opening pull requests.
+
+The types are the ones of the `github` package, which is the reference
+implementation.
+*/
+package backend
+
+import (
+	"context"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+// Brancher manages the branches the links are written on.
+type Brancher interface {
+	GetDefaultBranch(ctx context.Context, r github.Repo) (github.Branch, error)
+	GetDefaultBranchName(ctx context.Context, r github.Repo) (string, error)
+	GetBaseAndHeadBranches(ctx context.Context, r github.Repo, headName string) (
+		base github.Branch, head github.Branch,
+		err error,
+	)
+	DeleteBranch(ctx context.Context, r github.Repo, name string) error
+	ResetBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error)
+	Compare(ctx context.Context, r github.Repo, base, head string) (github.Comparison, error)
+}
+
+// Puller manages the pull requests opened for the head branches.
+type Puller interface {
+	GetPull(ctx context.Context, r github.Repo, base, head string) (github.Pull, error)
+	GetOrCreatePull(
+		ctx context.Context,
+		r github.Repo,
+		base, head, title, body string,
+		draft bool,
+	) (github.Pull, error)
+	UpdatePull(ctx context.Context, p github.Pull, title, body string) (github.Pull, error)
+	ConvertToDraft(ctx context.Context, p github.Pull) error
+	ClosePull(ctx context.Context, p github.Pull) error
+	MergePull(ctx context.Context, p github.Pull, method string) error
+	EnableAutoMerge(ctx context.Context, p github.Pull, method string) error
+
+	AddComment(ctx context.Context, p github.Pull, comment string) error
+	AddLabels(ctx context.Context, p github.Pull, labels []string) error
+	AddAssignees(ctx context.Context, p github.Pull, assignees []string) error
+	RequestReviewers(ctx context.Context, p github.Pull, reviewers, teams []string) error
+	SetMilestone(ctx context.Context, p github.Pull, milestone int) error
+}
+
+// Backend is a forge action-ln can sync the links on.
+type Backend interface {
+	github.GetterUpdater
+	Brancher
+	Puller
+}
+
+var _ Backend = (*github.GitHub)(nil)
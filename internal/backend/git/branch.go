@@ -0,0 +1,230 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const branchPrefix = "refs/heads/"
+
+var errInvalidOutput = errors.New("invalid git output")
+
+func (g *Git) GetRepo(ctx context.Context, r *github.Repo) error {
+	out, err := g.git(ctx, *r, "", nil, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get repo: %w", err)
+	}
+
+	r.DefaultBranch = strings.TrimSpace(out)
+
+	return nil
+}
+
+func (g *Git) GetDefaultBranchName(ctx context.Context, r github.Repo) (string, error) {
+	log.Debug("Get default branch name", "repo", r)
+
+	if err := g.GetRepo(ctx, &r); err != nil {
+		return "", err
+	}
+
+	return r.DefaultBranch, nil
+}
+
+func (g *Git) GetDefaultBranch(ctx context.Context, r github.Repo) (github.Branch, error) {
+	name, err := g.GetDefaultBranchName(ctx, r)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	return g.GetBranch(ctx, r, name)
+}
+
+func (g *Git) GetBranch(ctx context.Context, r github.Repo, name string) (github.Branch, error) {
+	log.Debug("Get branch", "repo", r, "name", name)
+
+	sha, ok, err := g.revParse(ctx, r, branchPrefix+name)
+	if err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrGetBranch, err)
+	}
+
+	if !ok {
+		return github.Branch{}, github.ErrNoBranch
+	}
+
+	return github.Branch{Name: name, Commit: github.Commit{SHA: sha}}, nil
+}
+
+func (g *Git) CreateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Create branch", "repo", r, "name", name, "sha", sha)
+
+	// NOTE: The empty old value makes git refuse to overwrite the branch.
+	if _, err := g.git(ctx, r, "", nil, "update-ref", branchPrefix+name, sha, ""); err != nil {
+		if _, getErr := g.GetBranch(ctx, r, name); getErr == nil {
+			return github.Branch{}, github.ErrBranchExists
+		}
+
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrCreateBranch, err)
+	}
+
+	return github.Branch{Name: name, Commit: github.Commit{SHA: sha}, New: true}, nil
+}
+
+func (g *Git) GetOrCreateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	b, err := g.GetBranch(ctx, r, name)
+	if err == nil {
+		return b, nil
+	}
+
+	if !errors.Is(err, github.ErrNoBranch) {
+		return b, err
+	}
+
+	return g.CreateBranch(ctx, r, name, sha)
+}
+
+func (g *Git) GetBaseAndHeadBranches(ctx context.Context, r github.Repo, headName string) (
+	base github.Branch, head github.Branch,
+	err error,
+) {
+	if base, err = g.GetDefaultBranch(ctx, r); err != nil {
+		return base, head, err
+	}
+
+	if head, err = g.GetOrCreateBranch(ctx, r, headName, base.Commit.SHA); err != nil {
+		return base, head, err
+	}
+
+	return base, head, nil
+}
+
+func (g *Git) DeleteBranch(ctx context.Context, r github.Repo, name string) error {
+	log.Debug("Delete branch", "repo", r, "name", name)
+
+	if _, err := g.git(ctx, r, "", nil, "update-ref", "-d", branchPrefix+name); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrDeleteBranch, err)
+	}
+
+	return nil
+}
+
+func (g *Git) UpdateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Update branch", "repo", r, "name", name, "sha", sha)
+
+	if _, err := g.git(ctx, r, "", nil, "update-ref", branchPrefix+name, sha); err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrUpdateBranch, err)
+	}
+
+	return github.Branch{Name: name, Commit: github.Commit{SHA: sha}}, nil
+}
+
+// ResetBranch moves the branch to sha, even if it's not a descendant.
+func (g *Git) ResetBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Reset branch", "repo", r, "name", name, "sha", sha)
+
+	if _, err := g.git(ctx, r, "", nil, "update-ref", branchPrefix+name, sha); err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrResetBranch, err)
+	}
+
+	return github.Branch{Name: name, Commit: github.Commit{SHA: sha}}, nil
+}
+
+func (g *Git) Compare(ctx context.Context, r github.Repo, base, head string) (github.Comparison, error) {
+	out, err := g.git(ctx, r, "", nil, "rev-list", "--left-right", "--count", base+"..."+head)
+	if err != nil {
+		return github.Comparison{}, fmt.Errorf("%w %s...%s: %w", github.ErrCompare, base, head, err)
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 { //nolint:mnd // Left and right counts.
+		return github.Comparison{}, fmt.Errorf("%w %s...%s: %w: %q", github.ErrCompare, base, head, errInvalidOutput, out)
+	}
+
+	c := github.Comparison{}
+	c.BehindBy, _ = strconv.Atoi(fields[0])
+	c.AheadBy, _ = strconv.Atoi(fields[1])
+
+	switch {
+	case c.AheadBy == 0 && c.BehindBy == 0:
+		c.Status = github.CompareIdentical
+	case c.BehindBy == 0:
+		c.Status = github.CompareAhead
+	case c.AheadBy == 0:
+		c.Status = github.CompareBehind
+	default:
+		c.Status = github.CompareDiverged
+	}
+
+	return c, nil
+}
+
+func (g *Git) GetTags(ctx context.Context, r github.Repo) ([]github.Tag, error) {
+	// NOTE: Annotated tags are peeled to their commit.
+	out, err := g.git(ctx, r, "", nil,
+		"for-each-ref", "--format=%(refname:short)%00%(objectname)%00%(*objectname)", "refs/tags",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %w", github.ErrGetTags, r, err)
+	}
+
+	tags := []github.Tag{}
+
+	for line := range strings.Lines(out) {
+		fields := strings.Split(strings.TrimSuffix(line, "\n"), "\x00")
+		if len(fields) != 3 { //nolint:mnd // Name, object and peeled object.
+			return nil, fmt.Errorf("%w for %s: %w: %q", github.ErrGetTags, r, errInvalidOutput, line)
+		}
+
+		sha := fields[1]
+		if fields[2] != "" {
+			sha = fields[2]
+		}
+
+		tags = append(tags, github.Tag{Name: fields[0], Commit: github.Commit{SHA: sha}})
+	}
+
+	return tags, nil
+}
+
+// GetLatestRelease always fails, as git has no releases.
+func (g *Git) GetLatestRelease(_ context.Context, r github.Repo) (github.Release, error) {
+	return github.Release{}, fmt.Errorf("%w for %s: %w", github.ErrGetRelease, r, errors.ErrUnsupported)
+}
+
+func (g *Git) GetCommits(ctx context.Context, r github.Repo, ref, path string, limit int) ([]github.RepoCommit, error) {
+	args := []string{"log", "-n", strconv.Itoa(limit), "--format=%H%x00%an%x00%B%x1e", refOrHead(ref), "--"}
+	if path != "" {
+		args = append(args, path)
+	}
+
+	out, err := g.git(ctx, r, "", nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s:%s@%s: %w", github.ErrGetCommits, r, path, ref, err)
+	}
+
+	commits := []github.RepoCommit{}
+
+	for record := range strings.SplitSeq(out, "\x1e") {
+		record = strings.TrimPrefix(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x00", 3) //nolint:mnd // SHA, author and message.
+		if len(fields) != 3 {                       //nolint:mnd // SHA, author and message.
+			return nil, fmt.Errorf("%w for %s:%s@%s: %w: %q", github.ErrGetCommits, r, path, ref, errInvalidOutput, record)
+		}
+
+		c := github.RepoCommit{SHA: fields[0], Author: github.User{Login: fields[1]}}
+		c.Commit.Message = strings.TrimRight(fields[2], "\n")
+
+		commits = append(commits, c)
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,207 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	// lsTreeFields are the fields before the path in `git ls-tree -l`.
+	lsTreeFields = 4
+
+	emptySHA = "0000000000000000000000000000000000000000"
+)
+
+var errInvalidTree = errors.New("invalid tree entry")
+
+func (g *Git) GetFile(ctx context.Context, f *github.File) error {
+	if err := g.GetFileInfo(ctx, f); err != nil {
+		return err
+	}
+
+	content, err := g.git(ctx, f.Repo, "", nil, "cat-file", "blob", f.SHA)
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrGetFile, err)
+	}
+
+	f.Content = content
+
+	return nil
+}
+
+func (g *Git) GetFileInfo(ctx context.Context, f *github.File) error {
+	tree, ok, err := g.revParse(ctx, f.Repo, refOrHead(f.Ref)+"^{tree}")
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrGetFile, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s", github.ErrMissingFile, f)
+	}
+
+	entries, err := g.lsTree(ctx, f.Repo, tree, "--", f.Path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrGetFile, err)
+	}
+
+	for _, e := range entries {
+		if e.Path != f.Path || e.Type != github.TypeBlob {
+			continue
+		}
+
+		dir, _ := g.dir(f.Repo)
+
+		f.Name = path.Base(e.Path)
+		f.SHA = e.SHA
+		f.Size = e.Size
+		f.HTMLURL = Scheme + filepath.Join(dir, e.Path)
+		f.Content = ""
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", github.ErrMissingFile, f)
+}
+
+func (g *Git) GetTree(ctx context.Context, r github.Repo, ref string) (github.Tree, error) {
+	log.Debug("Get tree", "repo", r, "ref", ref)
+
+	sha, ok, err := g.revParse(ctx, r, refOrHead(ref)+"^{tree}")
+	if err != nil {
+		return github.Tree{}, fmt.Errorf("%w: %w", github.ErrGetTree, err)
+	}
+
+	if !ok {
+		return github.Tree{}, fmt.Errorf("%w: %s@%s not found", github.ErrGetTree, r, ref)
+	}
+
+	entries, err := g.lsTree(ctx, r, sha, "-r", "-t")
+	if err != nil {
+		return github.Tree{}, fmt.Errorf("%w: %w", github.ErrGetTree, err)
+	}
+
+	return github.Tree{SHA: sha, Entries: entries}, nil
+}
+
+// lsTree lists the entries of the tree.
+func (g *Git) lsTree(ctx context.Context, r github.Repo, tree string, args ...string) ([]github.TreeEntry, error) {
+	out, err := g.git(ctx, r, "", nil, append([]string{"ls-tree", "-l", "-z", "--full-tree", tree}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []github.TreeEntry{}
+
+	for line := range strings.SplitSeq(out, "\x00") {
+		if line == "" {
+			continue
+		}
+
+		info, p, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+
+		if !ok || len(fields) != lsTreeFields {
+			return nil, fmt.Errorf("%w: %q", errInvalidTree, line)
+		}
+
+		// NOTE: Trees have no size, shown as `-`.
+		size, _ := strconv.Atoi(fields[3])
+
+		entries = append(entries, github.TreeEntry{
+			Mode: fields[0],
+			Type: fields[1],
+			SHA:  fields[2],
+			Size: size,
+			Path: p,
+		})
+	}
+
+	return entries, nil
+}
+
+func (g *Git) CreateBlob(ctx context.Context, r github.Repo, content string) (string, error) {
+	out, err := g.git(ctx, r, content, nil, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", github.ErrCreateBlob, err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// CreateTree writes the entries on top of baseTree, in a temporary index.
+// An entry without SHA deletes the file at its path.
+func (g *Git) CreateTree(
+	ctx context.Context,
+	r github.Repo,
+	baseTree string,
+	entries []github.TreeEntry,
+) (github.Tree, error) {
+	log.Debug("Create tree", "repo", r, "base", baseTree, "entries", entries)
+
+	tmp, err := os.MkdirTemp("", "action-ln-index-")
+	if err != nil {
+		return github.Tree{}, fmt.Errorf("%w: %w", github.ErrCreateTree, err)
+	}
+	defer os.RemoveAll(tmp)
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, "index")}
+
+	if baseTree != "" {
+		if _, err := g.git(ctx, r, "", env, "read-tree", baseTree); err != nil {
+			return github.Tree{}, fmt.Errorf("%w: %w", github.ErrCreateTree, err)
+		}
+	}
+
+	info := strings.Builder{}
+
+	for _, e := range entries {
+		if e.SHA == "" {
+			fmt.Fprintf(&info, "0 %s\t%s\x00", emptySHA, e.Path)
+
+			continue
+		}
+
+		fmt.Fprintf(&info, "%s %s\t%s\x00", e.Mode, e.SHA, e.Path)
+	}
+
+	if _, err := g.git(ctx, r, info.String(), env, "update-index", "-z", "--index-info"); err != nil {
+		return github.Tree{}, fmt.Errorf("%w: %w", github.ErrCreateTree, err)
+	}
+
+	out, err := g.git(ctx, r, "", env, "write-tree")
+	if err != nil {
+		return github.Tree{}, fmt.Errorf("%w: %w", github.ErrCreateTree, err)
+	}
+
+	return github.Tree{SHA: strings.TrimSpace(out)}, nil
+}
+
+func (g *Git) CreateCommit(
+	ctx context.Context,
+	r github.Repo,
+	message, tree string,
+	parents []string,
+) (github.Commit, error) {
+	log.Debug("Create commit", "repo", r, "tree", tree, "parents", parents)
+
+	args := []string{"commit-tree", tree}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+
+	out, err := g.git(ctx, r, message, g.identity(ctx, r), args...)
+	if err != nil {
+		return github.Commit{}, fmt.Errorf("%w: %w", github.ErrCreateCommit, err)
+	}
+
+	return github.Commit{SHA: strings.TrimSpace(out)}, nil
+}
@@ -0,0 +1,129 @@
+/*
+Package git implements a backend on local git repositories, without any forge.
+
+The repository `owner/repo` is found at `<root>/owner/repo.git` or
+`<root>/owner/repo`. Files are read from and written to the refs directly,
+so the repositories should be bare: the checked out files of a worktree are
+not updated.
+
+Git has no pull requests, they are stored in `<git dir>/action-ln/pulls.json`.
+*/
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+const (
+	// Scheme prefixes the endpoints served by this backend.
+	Scheme = "file://"
+
+	defaultName  = "action-ln"
+	defaultEmail = "action-ln@localhost"
+)
+
+var (
+	errGit         = errors.New("git command failed")
+	errMissingRepo = errors.New("repository not found")
+)
+
+var _ backend.Backend = (*Git)(nil)
+
+type Git struct {
+	root string
+}
+
+// New returns a backend serving the repositories under root, either a path or
+// a `file://` URL.
+func New(root string) *Git {
+	return &Git{root: strings.TrimPrefix(root, Scheme)}
+}
+
+// dir returns the directory of the repository.
+func (g *Git) dir(r github.Repo) (string, error) {
+	for _, name := range []string{r.Repo + ".git", r.Repo} {
+		rel := filepath.Join(r.Owner.Login, name)
+		if !filepath.IsLocal(rel) {
+			continue
+		}
+
+		dir := filepath.Join(g.root, rel)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s in %s", errMissingRepo, r, g.root)
+}
+
+// git runs the git command in the repository, with stdin as input.
+func (g *Git) git(ctx context.Context, r github.Repo, stdin string, env []string, args ...string) (string, error) {
+	dir, err := g.dir(r)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), env...)
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: git %s: %w: %s", errGit, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// revParse returns the object the revision points to, and false if it doesn't
+// exist.
+func (g *Git) revParse(ctx context.Context, r github.Repo, rev string) (string, bool, error) {
+	if _, err := g.dir(r); err != nil {
+		return "", false, err
+	}
+
+	out, err := g.git(ctx, r, "", nil, "rev-parse", "--verify", "--quiet", "--end-of-options", rev)
+	if err != nil {
+		//nolint:nilerr // A missing revision exits with an error, without any output.
+		return "", false, nil
+	}
+
+	return strings.TrimSpace(out), true, nil
+}
+
+// refOrHead returns the ref to read, HEAD if empty.
+func refOrHead(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+
+	return ref
+}
+
+// identity sets the author and committer, unless git already knows them.
+func (g *Git) identity(ctx context.Context, r github.Repo) []string {
+	env := []string{}
+
+	if out, err := g.git(ctx, r, "", nil, "config", "user.name"); err != nil || strings.TrimSpace(out) == "" {
+		env = append(env, "GIT_AUTHOR_NAME="+defaultName, "GIT_COMMITTER_NAME="+defaultName)
+	}
+
+	if out, err := g.git(ctx, r, "", nil, "config", "user.email"); err != nil || strings.TrimSpace(out) == "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+defaultEmail, "GIT_COMMITTER_EMAIL="+defaultEmail)
+	}
+
+	return env
+}
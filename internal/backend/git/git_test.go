@@ -0,0 +1,415 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+//nolint:gochecknoglobals // Shared test repository.
+var repo = github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo"}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=author", "GIT_AUTHOR_EMAIL=author@example.com",
+		"GIT_COMMITTER_NAME=author", "GIT_COMMITTER_EMAIL=author@example.com",
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
+
+// setup creates the bare repository owner/repo, with a `trunk` branch holding
+// two commits, and a `v1.0.0` tag on the first one.
+func setup(t *testing.T) *Git {
+	t.Helper()
+
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+
+	if err := os.MkdirAll(filepath.Join(work, "dir"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	run(t, work, "init", "--quiet", "--initial-branch", "trunk")
+
+	for _, c := range []struct{ file, content, msg string }{
+		{"a", "content a v1", "first"},
+		{"dir/b", "content b", "second\n\nbody"},
+	} {
+		if err := os.WriteFile(filepath.Join(work, c.file), []byte(c.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		run(t, work, "add", "-A")
+		run(t, work, "commit", "--quiet", "-m", c.msg)
+	}
+
+	run(t, work, "tag", "-a", "v1.0.0", "-m", "v1.0.0", "HEAD~1")
+	run(t, root, "clone", "--quiet", "--bare", work, filepath.Join(root, "owner", "repo.git"))
+
+	return New(Scheme + root)
+}
+
+func TestGetFile(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t)
+
+	t.Run("reads a file at a ref", func(t *testing.T) {
+		t.Parallel()
+
+		for ref, want := range map[string]string{"": "content b", "trunk": "content b"} {
+			f := github.File{Repo: repo, Path: "dir/b", Ref: ref}
+			if err := g.GetFile(t.Context(), &f); err != nil {
+				t.Fatal(err)
+			}
+
+			if f.Content != want || f.SHA != github.BlobSHA(want) || f.Name != "b" {
+				t.Fatalf("want %q but got %#v", want, f)
+			}
+		}
+	})
+
+	t.Run("reads a file info", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo, Path: "a", Ref: "v1.0.0"}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.SHA != github.BlobSHA("content a v1") || f.Size != len("content a v1") || f.Content != "" {
+			t.Fatalf("want the info only but got %#v", f)
+		}
+	})
+
+	t.Run("fails on missing files", func(t *testing.T) {
+		t.Parallel()
+
+		for _, f := range []github.File{
+			{Repo: repo, Path: "missing"},
+			{Repo: repo, Path: "dir"},
+			{Repo: repo, Path: "dir/b", Ref: "v1.0.0"},
+			{Repo: repo, Path: "a", Ref: "missing"},
+		} {
+			if err := g.GetFile(t.Context(), &f); !errors.Is(err, github.ErrMissingFile) {
+				t.Fatalf("want %v for %s but got %v", github.ErrMissingFile, f, err)
+			}
+		}
+	})
+
+	t.Run("fails on a missing repo", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: github.Repo{Owner: github.User{Login: ".."}, Repo: "repo"}, Path: "a"}
+		if err := g.GetFile(t.Context(), &f); !errors.Is(err, errMissingRepo) {
+			t.Fatalf("want %v but got %v", errMissingRepo, err)
+		}
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t)
+
+	tree, err := g.GetTree(t.Context(), repo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a", "dir/b"}
+	if got := tree.Blobs(); !slices.Equal(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t)
+	ctx := t.Context()
+
+	base, head, err := g.GetBaseAndHeadBranches(ctx, repo, "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if base.Name != "trunk" || !head.New || head.Commit.SHA != base.Commit.SHA {
+		t.Fatalf("want a new head on trunk but got %#v and %#v", base, head)
+	}
+
+	blob, err := g.CreateBlob(ctx, repo, "content c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headTree, err := g.GetTree(ctx, repo, head.Commit.SHA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := g.CreateTree(ctx, repo, headTree.SHA, []github.TreeEntry{
+		{Path: "c", Mode: github.ModeFile, Type: github.TypeBlob, SHA: blob},
+		{Path: "a", Mode: github.ModeFile, Type: github.TypeBlob},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := g.CreateCommit(ctx, repo, "message", tree.SHA, []string{head.Commit.SHA})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.UpdateBranch(ctx, repo, head.Name, commit.SHA); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := g.GetTree(ctx, repo, head.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"c", "dir/b"}; !slices.Equal(want, got.Blobs()) {
+		t.Fatalf("want %v but got %v", want, got.Blobs())
+	}
+
+	c, err := g.Compare(ctx, repo, base.Name, head.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Status != github.CompareAhead || c.AheadBy != 1 || c.Behind() {
+		t.Fatalf("want ahead by 1 but got %#v", c)
+	}
+
+	if _, err := g.CreateBranch(ctx, repo, head.Name, base.Commit.SHA); !errors.Is(err, github.ErrBranchExists) {
+		t.Fatalf("want %v but got %v", github.ErrBranchExists, err)
+	}
+
+	if err := g.DeleteBranch(ctx, repo, head.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.GetBranch(ctx, repo, head.Name); !errors.Is(err, github.ErrNoBranch) {
+		t.Fatalf("want %v but got %v", github.ErrNoBranch, err)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t)
+
+	tags, err := g.GetTags(t.Context(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, ok, err := g.revParse(t.Context(), repo, "trunk~1")
+	if err != nil || !ok {
+		t.Fatalf("want the first commit but got %v", err)
+	}
+
+	want := []github.Tag{{Name: "v1.0.0", Commit: github.Commit{SHA: first}}}
+	if !slices.Equal(want, tags) {
+		t.Fatalf("want %v but got %v", want, tags)
+	}
+}
+
+func TestGetCommits(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t)
+
+	commits, err := g.GetCommits(t.Context(), repo, "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, c := range commits {
+		got = append(got, c.Author.Login+": "+c.Commit.Message)
+	}
+
+	want := []string{"author: second\n\nbody", "author: first"}
+	if !slices.Equal(want, got) {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+
+	commits, err = g.GetCommits(t.Context(), repo, "trunk", "a", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commits) != 1 || commits[0].Title() != "first" {
+		t.Fatalf("want the first commit but got %#v", commits)
+	}
+}
+
+func TestPull(t *testing.T) {
+	t.Parallel()
+
+	setupPull := func(t *testing.T) (*Git, github.Pull) {
+		t.Helper()
+
+		g := setup(t)
+
+		base, err := g.GetDefaultBranch(t.Context(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.CreateBranch(t.Context(), repo, "head", base.Commit.SHA); err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := g.GetOrCreatePull(t.Context(), repo, "trunk", "head", "title", "body", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return g, p
+	}
+
+	t.Run("stores the pull", func(t *testing.T) {
+		t.Parallel()
+
+		g, p := setupPull(t)
+		ctx := t.Context()
+
+		if !p.New || p.Number != 1 {
+			t.Fatalf("want a new pull #1 but got %#v", p)
+		}
+
+		if err := g.AddLabels(ctx, p, []string{"a", "b"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.AddLabels(ctx, p, []string{"b", "c"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.UpdatePull(ctx, p, "new title", "new body"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.ConvertToDraft(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := g.GetOrCreatePull(ctx, repo, "trunk", "head", "", "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.New || got.Number != p.Number || !got.Draft {
+			t.Fatalf("want the existing pull but got %#v", got)
+		}
+
+		pulls, err := g.readPulls(ctx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if pulls[0].Title != "new title" || !slices.Equal(pulls[0].Labels, []string{"a", "b", "c"}) {
+			t.Fatalf("want the updated pull but got %#v", pulls[0])
+		}
+
+		if err := g.ClosePull(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.GetPull(ctx, repo, "trunk", "head"); !errors.Is(err, github.ErrNoPull) {
+			t.Fatalf("want %v but got %v", github.ErrNoPull, err)
+		}
+	})
+
+	for _, method := range []string{"merge", "squash", "rebase"} {
+		t.Run("merges with "+method, func(t *testing.T) {
+			t.Parallel()
+
+			g, p := setupPull(t)
+			ctx := t.Context()
+
+			head, err := g.GetBranch(ctx, repo, "head")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := "revert\n\nSynced-From: owner/repo:file@abc"
+
+			commit, err := g.CreateCommit(ctx, repo, msg, "trunk~1^{tree}", []string{head.Commit.SHA})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := g.UpdateBranch(ctx, repo, "head", commit.SHA); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := g.MergePull(ctx, p, method); err != nil {
+				t.Fatal(err)
+			}
+
+			// NOTE: Squashing doesn't keep the head commits, only its content.
+			trunkTree, _, _ := g.revParse(ctx, repo, "trunk^{tree}")
+			headTree, _, _ := g.revParse(ctx, repo, "head^{tree}")
+
+			if trunkTree != headTree {
+				t.Fatalf("want trunk to have the head tree %q but got %q", headTree, trunkTree)
+			}
+
+			commits, err := g.GetCommits(ctx, repo, "trunk", "", 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.ContainsFunc(commits, func(c github.RepoCommit) bool {
+				return strings.Contains(c.Commit.Message, "Synced-From: owner/repo:file@abc")
+			}) {
+				t.Fatalf("want the trailers kept on trunk but got %#v", commits)
+			}
+
+			pulls, err := g.readPulls(ctx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pulls[0].State != stateMerged {
+				t.Fatalf("want the pull merged but got %#v", pulls[0])
+			}
+		})
+	}
+
+	t.Run("fails to merge a pull behind base", func(t *testing.T) {
+		t.Parallel()
+
+		g, p := setupPull(t)
+		ctx := t.Context()
+
+		if _, err := g.ResetBranch(ctx, repo, "head", "trunk~1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.MergePull(ctx, p, "merge"); !errors.Is(err, github.ErrPullNotMergeable) {
+			t.Fatalf("want %v but got %v", github.ErrPullNotMergeable, err)
+		}
+
+		if err := g.EnableAutoMerge(ctx, p, "merge"); err != nil {
+			t.Fatalf("want no error but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,414 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	pullsDir  = "action-ln"
+	pullsFile = "pulls.json"
+
+	stateOpen   = "open"
+	stateClosed = "closed"
+	stateMerged = "merged"
+
+	methodSquash = "squash"
+	methodRebase = "rebase"
+)
+
+var errPulls = errors.New("failed to access the pulls")
+
+// pull is a pull request, stored next to the repository.
+type pull struct {
+	Number        int      `json:"number"`
+	State         string   `json:"state"`
+	Base          string   `json:"base"`
+	Head          string   `json:"head"`
+	Title         string   `json:"title"`
+	Body          string   `json:"body"`
+	Draft         bool     `json:"draft"`
+	Labels        []string `json:"labels,omitempty"`
+	Assignees     []string `json:"assignees,omitempty"`
+	Reviewers     []string `json:"reviewers,omitempty"`
+	TeamReviewers []string `json:"team_reviewers,omitempty"`
+	Milestone     int      `json:"milestone,omitempty"`
+	Comments      []string `json:"comments,omitempty"`
+}
+
+// pullsPath returns the file storing the pulls, in the git directory.
+func (g *Git) pullsPath(ctx context.Context, r github.Repo) (string, error) {
+	out, err := g.git(ctx, r, "", nil, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(strings.TrimSpace(out), pullsDir, pullsFile), nil
+}
+
+func (g *Git) readPulls(ctx context.Context, r github.Repo) ([]pull, error) {
+	path, err := g.pullsPath(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []pull{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	pulls := []pull{}
+	if err := json.Unmarshal(content, &pulls); err != nil {
+		return nil, fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	return pulls, nil
+}
+
+func (g *Git) writePulls(ctx context.Context, r github.Repo, pulls []pull) error {
+	path, err := g.pullsPath(ctx, r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	content, err := json.MarshalIndent(pulls, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:mnd // Usual directory permissions.
+		return fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	if err := os.WriteFile(path, append(content, '\n'), 0o600); err != nil {
+		return fmt.Errorf("%w: %w", errPulls, err)
+	}
+
+	return nil
+}
+
+// updatePull applies update to the stored pull.
+func (g *Git) updatePull(ctx context.Context, p github.Pull, update func(*pull) error) error {
+	pulls, err := g.readPulls(ctx, p.Repo)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(pulls, func(s pull) bool { return s.Number == p.Number })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", github.ErrNoPull, p)
+	}
+
+	if err := update(&pulls[i]); err != nil {
+		return err
+	}
+
+	return g.writePulls(ctx, p.Repo, pulls)
+}
+
+func (g *Git) GetPull(ctx context.Context, r github.Repo, base, head string) (github.Pull, error) {
+	pulls, err := g.readPulls(ctx, r)
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrGetPull, err)
+	}
+
+	for _, p := range pulls {
+		if p.State == stateOpen && p.Base == base && p.Head == head {
+			return github.Pull{Number: p.Number, Draft: p.Draft, Repo: r}, nil
+		}
+	}
+
+	return github.Pull{}, github.ErrNoPull
+}
+
+func (g *Git) CreatePull(
+	ctx context.Context,
+	r github.Repo,
+	base, head, title, body string,
+	draft bool,
+) (github.Pull, error) {
+	pulls, err := g.readPulls(ctx, r)
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrCreatePull, err)
+	}
+
+	number := 1
+
+	for _, p := range pulls {
+		if p.State == stateOpen && p.Base == base && p.Head == head {
+			return github.Pull{}, github.ErrPullExists
+		}
+
+		number = max(number, p.Number+1)
+	}
+
+	pulls = append(pulls, pull{
+		Number: number,
+		State:  stateOpen,
+		Base:   base,
+		Head:   head,
+		Title:  title,
+		Body:   body,
+		Draft:  draft,
+	})
+
+	if err := g.writePulls(ctx, r, pulls); err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrCreatePull, err)
+	}
+
+	return github.Pull{Number: number, Repo: r, New: true}, nil
+}
+
+func (g *Git) GetOrCreatePull(
+	ctx context.Context,
+	r github.Repo,
+	base, head, title, body string,
+	draft bool,
+) (github.Pull, error) {
+	p, err := g.GetPull(ctx, r, base, head)
+	if err == nil {
+		return p, nil
+	}
+
+	if !errors.Is(err, github.ErrNoPull) {
+		return github.Pull{}, err
+	}
+
+	return g.CreatePull(ctx, r, base, head, title, body, draft)
+}
+
+func (g *Git) UpdatePull(ctx context.Context, p github.Pull, title, body string) (github.Pull, error) {
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Title = title
+		s.Body = body
+
+		return nil
+	})
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrUpdatePull, err)
+	}
+
+	return p, nil
+}
+
+func (g *Git) ConvertToDraft(ctx context.Context, p github.Pull) error {
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Draft = true
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrConvertToDraft, err)
+	}
+
+	return nil
+}
+
+func (g *Git) ClosePull(ctx context.Context, p github.Pull) error {
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.State = stateClosed
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrClosePull, err)
+	}
+
+	return nil
+}
+
+// MergePull merges head into base, only if base has not moved since head
+// branched from it, as conflicts can't be solved here.
+func (g *Git) MergePull(ctx context.Context, p github.Pull, method string) error {
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		if _, err := g.git(ctx, p.Repo, "", nil,
+			"merge-base", "--is-ancestor", branchPrefix+s.Base, branchPrefix+s.Head,
+		); err != nil {
+			return fmt.Errorf("%w: %s is behind %s", github.ErrPullNotMergeable, s.Head, s.Base)
+		}
+
+		sha, err := g.merge(ctx, p.Repo, *s, method)
+		if err != nil {
+			return err
+		}
+
+		if _, err := g.UpdateBranch(ctx, p.Repo, s.Base, sha); err != nil {
+			return err
+		}
+
+		s.State = stateMerged
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, github.ErrPullNotMergeable):
+		return err
+	case err != nil:
+		return fmt.Errorf("%w: %w", github.ErrMergePull, err)
+	}
+
+	return nil
+}
+
+// merge returns the commit base is moved to, for each merge method.
+func (g *Git) merge(ctx context.Context, r github.Repo, p pull, method string) (string, error) {
+	head, ok, err := g.revParse(ctx, r, branchPrefix+p.Head)
+	if err != nil || !ok {
+		return "", fmt.Errorf("%w: %s", github.ErrNoBranch, p.Head)
+	}
+
+	// NOTE: head is on top of base, so rebasing is a fast-forward.
+	if method == methodRebase {
+		return head, nil
+	}
+
+	parents := []string{branchPrefix + p.Base}
+	if method != methodSquash {
+		parents = append(parents, head)
+	}
+
+	msg := fmt.Sprintf("%s (#%d)\n\n%s", p.Title, p.Number, p.Body)
+
+	// NOTE: Squashing drops the head commits, keep their message and trailers.
+	if method == methodSquash {
+		commits, err := g.GetCommits(ctx, r, head, "", 1)
+		if err != nil {
+			return "", err
+		}
+
+		if len(commits) > 0 {
+			msg = fmt.Sprintf("%s (#%d)\n\n%s", p.Title, p.Number, commits[0].Commit.Message)
+		}
+	}
+
+	commit, err := g.CreateCommit(ctx, r, msg, head+"^{tree}", parents)
+	if err != nil {
+		return "", err
+	}
+
+	return commit.SHA, nil
+}
+
+// EnableAutoMerge merges the pull right away, as there are no checks to wait
+// for. If it can't be merged, it is retried on the next run.
+func (g *Git) EnableAutoMerge(ctx context.Context, p github.Pull, method string) error {
+	err := g.MergePull(ctx, p, method)
+	if errors.Is(err, github.ErrPullNotMergeable) {
+		log.Warn("Pull request cannot be merged yet", "pull", p, "err", err)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrEnableAutoMerge, err)
+	}
+
+	return nil
+}
+
+func (g *Git) AddComment(ctx context.Context, p github.Pull, comment string) error {
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Comments = append(s.Comments, comment)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddComment, err)
+	}
+
+	return nil
+}
+
+func (g *Git) AddLabels(ctx context.Context, p github.Pull, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Labels = union(s.Labels, labels)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddLabels, err)
+	}
+
+	return nil
+}
+
+func (g *Git) AddAssignees(ctx context.Context, p github.Pull, assignees []string) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Assignees = union(s.Assignees, assignees)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddAssignees, err)
+	}
+
+	return nil
+}
+
+func (g *Git) RequestReviewers(ctx context.Context, p github.Pull, reviewers, teams []string) error {
+	if len(reviewers) == 0 && len(teams) == 0 {
+		return nil
+	}
+
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Reviewers = union(s.Reviewers, reviewers)
+		s.TeamReviewers = union(s.TeamReviewers, teams)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrRequestReviewers, err)
+	}
+
+	return nil
+}
+
+func (g *Git) SetMilestone(ctx context.Context, p github.Pull, milestone int) error {
+	if milestone == 0 {
+		return nil
+	}
+
+	err := g.updatePull(ctx, p, func(s *pull) error {
+		s.Milestone = milestone
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrSetMilestone, err)
+	}
+
+	return nil
+}
+
+// union appends the values of b missing from a.
+func union(a, b []string) []string {
+	for _, v := range b {
+		if !slices.Contains(a, v) {
+			a = append(a, v)
+		}
+	}
+
+	return a
+}
@@ -0,0 +1,357 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+// branch is a branch or a tag, as both have a name and a commit.
+type branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+func (b branch) branch() github.Branch {
+	return github.Branch{Name: b.Name, Commit: github.Commit{SHA: b.Commit.ID}}
+}
+
+func branchPath(r github.Repo, name string) string {
+	return projectPath(r) + "/repository/branches/" + url.PathEscape(name)
+}
+
+// https://docs.gitlab.com/api/projects/#get-a-single-project
+func (g *GitLab) GetRepo(ctx context.Context, r *github.Repo) error {
+	res := struct {
+		DefaultBranch string `json:"default_branch"`
+	}{}
+
+	if _, _, err := g.req(ctx, http.MethodGet, projectPath(*r), nil, &res); err != nil {
+		return fmt.Errorf("failed to get repo: %w", err)
+	}
+
+	r.DefaultBranch = res.DefaultBranch
+
+	return nil
+}
+
+func (g *GitLab) GetDefaultBranchName(ctx context.Context, r github.Repo) (string, error) {
+	log.Debug("Get default branch name", "repo", r)
+
+	if err := g.GetRepo(ctx, &r); err != nil {
+		return "", err
+	}
+
+	return r.DefaultBranch, nil
+}
+
+func (g *GitLab) GetDefaultBranch(ctx context.Context, r github.Repo) (github.Branch, error) {
+	name, err := g.GetDefaultBranchName(ctx, r)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	return g.GetBranch(ctx, r, name)
+}
+
+// https://docs.gitlab.com/api/branches/#get-single-repository-branch
+func (g *GitLab) GetBranch(ctx context.Context, r github.Repo, name string) (github.Branch, error) {
+	log.Debug("Get branch", "repo", r, "name", name)
+
+	b := branch{}
+
+	status, _, err := g.req(ctx, http.MethodGet, branchPath(r, name), nil, &b)
+	if status == http.StatusNotFound {
+		return github.Branch{}, github.ErrNoBranch
+	}
+
+	if err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrGetBranch, err)
+	}
+
+	return b.branch(), nil
+}
+
+// https://docs.gitlab.com/api/branches/#create-repository-branch
+func (g *GitLab) CreateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Create branch", "repo", r, "name", name, "sha", sha)
+
+	body := struct {
+		Branch string `json:"branch"`
+		Ref    string `json:"ref"`
+	}{
+		Branch: name,
+		Ref:    sha,
+	}
+
+	status, _, err := g.req(ctx, http.MethodPost, projectPath(r)+"/repository/branches", body, nil)
+	if err != nil {
+		// NOTE: 400 is also returned for invalid names or refs.
+		if status == http.StatusBadRequest {
+			if _, getErr := g.GetBranch(ctx, r, name); getErr == nil {
+				return github.Branch{}, github.ErrBranchExists
+			}
+		}
+
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrCreateBranch, err)
+	}
+
+	return github.Branch{Name: name, Commit: github.Commit{SHA: sha}, New: true}, nil
+}
+
+func (g *GitLab) GetOrCreateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	b, err := g.GetBranch(ctx, r, name)
+	if err == nil {
+		return b, nil
+	}
+
+	if !errors.Is(err, github.ErrNoBranch) {
+		return b, err
+	}
+
+	return g.CreateBranch(ctx, r, name, sha)
+}
+
+func (g *GitLab) GetBaseAndHeadBranches(ctx context.Context, r github.Repo, headName string) (
+	base github.Branch, head github.Branch,
+	err error,
+) {
+	if base, err = g.GetDefaultBranch(ctx, r); err != nil {
+		return base, head, err
+	}
+
+	if head, err = g.GetOrCreateBranch(ctx, r, headName, base.Commit.SHA); err != nil {
+		return base, head, err
+	}
+
+	return base, head, nil
+}
+
+// https://docs.gitlab.com/api/branches/#delete-repository-branch
+func (g *GitLab) DeleteBranch(ctx context.Context, r github.Repo, name string) error {
+	log.Debug("Delete branch", "repo", r, "name", name)
+
+	if _, _, err := g.req(ctx, http.MethodDelete, branchPath(r, name), nil, nil); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrDeleteBranch, err)
+	}
+
+	return nil
+}
+
+// UpdateBranch sends the pending commit sha on the branch, or moves the
+// branch to the existing commit sha.
+func (g *GitLab) UpdateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Update branch", "repo", r, "name", name, "sha", sha)
+
+	var (
+		b   github.Branch
+		err error
+	)
+
+	if strings.HasPrefix(sha, pendingCommit) {
+		b, err = g.push(ctx, r, name, sha)
+	} else {
+		b, err = g.moveBranch(ctx, r, name, sha)
+	}
+
+	if err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrUpdateBranch, err)
+	}
+
+	return b, nil
+}
+
+// ResetBranch moves the branch to sha, even if it's not a descendant.
+func (g *GitLab) ResetBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	log.Debug("Reset branch", "repo", r, "name", name, "sha", sha)
+
+	b, err := g.moveBranch(ctx, r, name, sha)
+	if err != nil {
+		return github.Branch{}, fmt.Errorf("%w: %w", github.ErrResetBranch, err)
+	}
+
+	return b, nil
+}
+
+// moveBranch recreates the branch on sha, as GitLab can't move a branch.
+// If it can't be recreated, it's restored on its previous commit.
+func (g *GitLab) moveBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	old, err := g.GetBranch(ctx, r, name)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	if old.Commit.SHA == sha {
+		return old, nil
+	}
+
+	if err := g.DeleteBranch(ctx, r, name); err != nil {
+		return github.Branch{}, err
+	}
+
+	b, err := g.CreateBranch(ctx, r, name, sha)
+	if err != nil {
+		log.Warn("Restoring the branch", "repo", r, "name", name, "sha", old.Commit.SHA, "err", err)
+
+		if _, restoreErr := g.CreateBranch(ctx, r, name, old.Commit.SHA); restoreErr != nil {
+			return github.Branch{}, errors.Join(err, restoreErr)
+		}
+
+		return github.Branch{}, err
+	}
+
+	b.New = false
+
+	return b, nil
+}
+
+// Compare counts the commits of each side, with two comparisons.
+// https://docs.gitlab.com/api/repositories/#compare-branches-tags-or-commits
+func (g *GitLab) Compare(ctx context.Context, r github.Repo, base, head string) (github.Comparison, error) {
+	count := func(from, to string) (int, error) {
+		q := url.Values{"from": []string{from}, "to": []string{to}}
+		res := struct {
+			Commits []struct{} `json:"commits"`
+		}{}
+
+		p := fmt.Sprintf("%s/repository/compare?%s", projectPath(r), q.Encode())
+		if _, _, err := g.req(ctx, http.MethodGet, p, nil, &res); err != nil {
+			return 0, fmt.Errorf("%w %s...%s: %w", github.ErrCompare, base, head, err)
+		}
+
+		return len(res.Commits), nil
+	}
+
+	c := github.Comparison{}
+
+	var err error
+
+	if c.AheadBy, err = count(base, head); err != nil {
+		return github.Comparison{}, err
+	}
+
+	if c.BehindBy, err = count(head, base); err != nil {
+		return github.Comparison{}, err
+	}
+
+	switch {
+	case c.AheadBy == 0 && c.BehindBy == 0:
+		c.Status = github.CompareIdentical
+	case c.BehindBy == 0:
+		c.Status = github.CompareAhead
+	case c.AheadBy == 0:
+		c.Status = github.CompareBehind
+	default:
+		c.Status = github.CompareDiverged
+	}
+
+	return c, nil
+}
+
+// https://docs.gitlab.com/api/tags/#list-project-repository-tags
+func (g *GitLab) GetTags(ctx context.Context, r github.Repo) ([]github.Tag, error) {
+	tags := []github.Tag{}
+
+	for page := 1; ; page++ {
+		q := url.Values{
+			"per_page": []string{strconv.Itoa(perPage)},
+			"page":     []string{strconv.Itoa(page)},
+		}
+
+		pageTags := []branch{}
+
+		p := fmt.Sprintf("%s/repository/tags?%s", projectPath(r), q.Encode())
+		if _, _, err := g.req(ctx, http.MethodGet, p, nil, &pageTags); err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", github.ErrGetTags, r, err)
+		}
+
+		for _, t := range pageTags {
+			tags = append(tags, github.Tag{Name: t.Name, Commit: github.Commit{SHA: t.Commit.ID}})
+		}
+
+		if len(pageTags) < perPage {
+			return tags, nil
+		}
+	}
+}
+
+// https://docs.gitlab.com/api/releases/#get-the-latest-release
+func (g *GitLab) GetLatestRelease(ctx context.Context, r github.Repo) (github.Release, error) {
+	res := struct {
+		TagName string `json:"tag_name"`
+		Links   struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}{}
+
+	p := projectPath(r) + "/releases/permalink/latest"
+	if _, _, err := g.req(ctx, http.MethodGet, p, nil, &res); err != nil {
+		return github.Release{}, fmt.Errorf("%w for %s: %w", github.ErrGetRelease, r, err)
+	}
+
+	return github.Release{TagName: res.TagName, HTMLURL: res.Links.Self}, nil
+}
+
+// GetCommits lists at most limit commits that touched path, from the newest on
+// ref. An empty ref lists the default branch.
+// https://docs.gitlab.com/api/commits/#list-repository-commits
+func (g *GitLab) GetCommits(ctx context.Context, r github.Repo, ref, path string, limit int) (
+	[]github.RepoCommit,
+	error,
+) {
+	commits := []github.RepoCommit{}
+	size := min(limit, perPage)
+
+	for page := 1; len(commits) < limit; page++ {
+		q := url.Values{
+			"per_page": []string{strconv.Itoa(size)},
+			"page":     []string{strconv.Itoa(page)},
+		}
+
+		if ref != "" {
+			q.Set("ref_name", ref)
+		}
+
+		if path != "" {
+			q.Set("path", path)
+		}
+
+		pageCommits := []struct {
+			ID         string `json:"id"`
+			Message    string `json:"message"`
+			AuthorName string `json:"author_name"`
+			WebURL     string `json:"web_url"`
+		}{}
+
+		p := fmt.Sprintf("%s/repository/commits?%s", projectPath(r), q.Encode())
+		if _, _, err := g.req(ctx, http.MethodGet, p, nil, &pageCommits); err != nil {
+			return nil, fmt.Errorf("%w for %s:%s@%s: %w", github.ErrGetCommits, r, path, ref, err)
+		}
+
+		for _, pc := range pageCommits {
+			c := github.RepoCommit{SHA: pc.ID, HTMLURL: pc.WebURL, Author: github.User{Login: pc.AuthorName}}
+			c.Commit.Message = strings.TrimRight(pc.Message, "\n")
+
+			commits = append(commits, c)
+		}
+
+		if len(pageCommits) < size {
+			break
+		}
+	}
+
+	if len(commits) > limit {
+		commits = commits[:limit]
+	}
+
+	return commits, nil
+}
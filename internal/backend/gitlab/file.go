@@ -0,0 +1,354 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	modeExecutable = "100755"
+
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+
+	encodingBase64 = "base64"
+
+	pendingTree   = "pending-tree-"
+	pendingCommit = "pending-commit-"
+)
+
+var (
+	errPendingCommit = errors.New("unknown pending commit")
+	errMissingBlob   = errors.New("unknown blob")
+	errBranchMoved   = errors.New("branch moved")
+)
+
+type file struct {
+	FileName string `json:"file_name"`
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+	BlobID   string `json:"blob_id"`
+}
+
+// tree is created by CreateTree, it holds the entries to write on top of the
+// base tree.
+type tree struct {
+	base    string
+	entries []github.TreeEntry
+}
+
+// commit is created by CreateCommit, and sent by UpdateBranch.
+type commit struct {
+	message string
+	tree    string
+	parents []string
+}
+
+func filePath(f *github.File) string {
+	q := url.Values{"ref": []string{refOrHead(f.Ref)}}
+
+	return fmt.Sprintf("%s/repository/files/%s?%s", projectPath(f.Repo), url.PathEscape(f.Path), q.Encode())
+}
+
+// https://docs.gitlab.com/api/repository_files/#get-file-from-repository
+func (g *GitLab) GetFile(ctx context.Context, f *github.File) error {
+	log.Debug("Get file", "file", f)
+
+	res := file{}
+
+	status, _, err := g.req(ctx, http.MethodGet, filePath(f), nil, &res)
+	if status == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", github.ErrMissingFile, f)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrGetFile, err)
+	}
+
+	if res.Encoding != encodingBase64 {
+		return fmt.Errorf("%w: unknown encoding %q", github.ErrDecodeFile, res.Encoding)
+	}
+
+	content, err := base64.StdEncoding.DecodeString(res.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrDecodeFile, err)
+	}
+
+	f.Name = res.FileName
+	f.SHA = res.BlobID
+	f.Size = res.Size
+	f.HTMLURL = g.webURL(f.Repo, "blob", refOrHead(f.Ref), f.Path)
+	f.Content = string(content)
+
+	return nil
+}
+
+// GetFileInfo reads the headers of the file, without its content.
+// https://docs.gitlab.com/api/repository_files/#get-file-metadata-only
+func (g *GitLab) GetFileInfo(ctx context.Context, f *github.File) error {
+	log.Debug("Get file info", "file", f)
+
+	status, header, err := g.req(ctx, http.MethodHead, filePath(f), nil, nil)
+	if status == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", github.ErrMissingFile, f)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrGetFile, err)
+	}
+
+	f.Name = path.Base(f.Path)
+	f.SHA = header.Get("X-Gitlab-Blob-Id")
+	f.Size, _ = strconv.Atoi(header.Get("X-Gitlab-Size"))
+	f.HTMLURL = g.webURL(f.Repo, "blob", refOrHead(f.Ref), f.Path)
+	f.Content = ""
+
+	return nil
+}
+
+// GetTree lists the tree at ref. GitLab doesn't expose the tree objects, so
+// the tree SHA is the ref itself.
+// https://docs.gitlab.com/api/repositories/#list-repository-tree
+func (g *GitLab) GetTree(ctx context.Context, r github.Repo, ref string) (github.Tree, error) {
+	log.Debug("Get tree", "repo", r, "ref", ref)
+
+	t := github.Tree{SHA: refOrHead(ref), Entries: []github.TreeEntry{}}
+
+	for page := 1; ; page++ {
+		q := url.Values{
+			"ref":       []string{t.SHA},
+			"recursive": []string{"true"},
+			"per_page":  []string{strconv.Itoa(perPage)},
+			"page":      []string{strconv.Itoa(page)},
+		}
+
+		entries := []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+			Path string `json:"path"`
+			Mode string `json:"mode"`
+		}{}
+
+		p := fmt.Sprintf("%s/repository/tree?%s", projectPath(r), q.Encode())
+		if _, _, err := g.req(ctx, http.MethodGet, p, nil, &entries); err != nil {
+			return github.Tree{}, fmt.Errorf("%w: %w", github.ErrGetTree, err)
+		}
+
+		for _, e := range entries {
+			t.Entries = append(t.Entries, github.TreeEntry{Path: e.Path, Mode: e.Mode, Type: e.Type, SHA: e.ID})
+		}
+
+		if len(entries) < perPage {
+			return t, nil
+		}
+	}
+}
+
+// CreateBlob keeps the content until the commit is sent.
+func (g *GitLab) CreateBlob(_ context.Context, _ github.Repo, content string) (string, error) {
+	sha := github.BlobSHA(content)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.blobs[sha] = content
+
+	return sha, nil
+}
+
+// CreateTree keeps the entries until the commit is sent.
+// An entry without SHA deletes the file at its path.
+func (g *GitLab) CreateTree(
+	_ context.Context,
+	r github.Repo,
+	baseTree string,
+	entries []github.TreeEntry,
+) (github.Tree, error) {
+	log.Debug("Create tree", "repo", r, "base", baseTree, "entries", entries)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.count++
+	sha := pendingTree + strconv.Itoa(g.count)
+	g.trees[sha] = tree{base: baseTree, entries: entries}
+
+	return github.Tree{SHA: sha, Entries: entries}, nil
+}
+
+// CreateCommit keeps the commit until UpdateBranch sends it.
+func (g *GitLab) CreateCommit(
+	_ context.Context,
+	r github.Repo,
+	message, treeSHA string,
+	parents []string,
+) (github.Commit, error) {
+	log.Debug("Create commit", "repo", r, "tree", treeSHA, "parents", parents)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.trees[treeSHA]; !ok {
+		return github.Commit{}, fmt.Errorf("%w: unknown tree %s", github.ErrCreateCommit, treeSHA)
+	}
+
+	g.count++
+	sha := pendingCommit + strconv.Itoa(g.count)
+	g.commits[sha] = commit{message: message, tree: treeSHA, parents: parents}
+
+	return github.Commit{SHA: sha}, nil
+}
+
+// pending returns the commit and its tree, if sha is a pending commit.
+func (g *GitLab) pending(sha string) (commit, tree, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c, ok := g.commits[sha]
+	if !ok {
+		return commit{}, tree{}, false
+	}
+
+	return c, g.trees[c.tree], true
+}
+
+// push sends the pending commit on the branch, on top of the commit's parent.
+// The branch must still be on the parent, or not exist yet.
+// NOTE: GitLab only starts from another commit with `force`, when the branch
+// exists.
+// https://docs.gitlab.com/api/commits/#create-a-commit-with-multiple-files-and-actions
+func (g *GitLab) push(ctx context.Context, r github.Repo, branch, sha string) (github.Branch, error) {
+	c, t, ok := g.pending(sha)
+	if !ok {
+		return github.Branch{}, fmt.Errorf("%w: %s", errPendingCommit, sha)
+	}
+
+	parent := t.base
+	if len(c.parents) > 0 {
+		parent = c.parents[0]
+	}
+
+	head, err := g.GetBranch(ctx, r, branch)
+	exists := err == nil
+
+	switch {
+	case errors.Is(err, github.ErrNoBranch):
+	case err != nil:
+		return github.Branch{}, err
+	case head.Commit.SHA != parent:
+		return github.Branch{}, fmt.Errorf("%w: %s is on %s, want %s", errBranchMoved, branch, head.Commit.SHA, parent)
+	}
+
+	actions, err := g.actions(ctx, r, parent, t.entries)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	if len(actions) == 0 {
+		log.Debug("Nothing to commit", "repo", r, "branch", branch)
+
+		return github.Branch{Name: branch, Commit: github.Commit{SHA: parent}}, nil
+	}
+
+	body := struct {
+		Branch   string   `json:"branch"`
+		StartSHA string   `json:"start_sha"`
+		Force    bool     `json:"force,omitempty"`
+		Message  string   `json:"commit_message"`
+		Actions  []action `json:"actions"`
+	}{
+		Branch:   branch,
+		StartSHA: parent,
+		Force:    exists,
+		Message:  c.message,
+		Actions:  actions,
+	}
+
+	res := struct {
+		ID string `json:"id"`
+	}{}
+
+	if _, _, err := g.req(ctx, http.MethodPost, projectPath(r)+"/repository/commits", body, &res); err != nil {
+		return github.Branch{}, err
+	}
+
+	g.mu.Lock()
+	delete(g.trees, c.tree)
+	delete(g.commits, sha)
+	g.mu.Unlock()
+
+	return github.Branch{Name: branch, Commit: github.Commit{SHA: res.ID}}, nil
+}
+
+type action struct {
+	Action          string `json:"action"`
+	FilePath        string `json:"file_path"`
+	Content         string `json:"content,omitempty"`
+	Encoding        string `json:"encoding,omitempty"`
+	ExecuteFilemode bool   `json:"execute_filemode,omitempty"`
+}
+
+// actions turns the entries into the commit's actions, GitLab needs to know if
+// the files already exist on parent.
+func (g *GitLab) actions(ctx context.Context, r github.Repo, parent string, entries []github.TreeEntry) (
+	[]action,
+	error,
+) {
+	actions := []action{}
+
+	for _, e := range entries {
+		f := github.File{Repo: r, Path: e.Path, Ref: parent}
+
+		exists := true
+		if err := g.GetFileInfo(ctx, &f); err != nil {
+			if !errors.Is(err, github.ErrMissingFile) {
+				return nil, err
+			}
+
+			exists = false
+		}
+
+		if e.SHA == "" {
+			if exists {
+				actions = append(actions, action{Action: actionDelete, FilePath: e.Path})
+			}
+
+			continue
+		}
+
+		g.mu.Lock()
+		content, ok := g.blobs[e.SHA]
+		g.mu.Unlock()
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %s for %s", errMissingBlob, e.SHA, e.Path)
+		}
+
+		a := action{
+			Action:          actionCreate,
+			FilePath:        e.Path,
+			Content:         base64.StdEncoding.EncodeToString([]byte(content)),
+			Encoding:        encodingBase64,
+			ExecuteFilemode: e.Mode == modeExecutable,
+		}
+
+		if exists {
+			a.Action = actionUpdate
+		}
+
+		actions = append(actions, a)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,143 @@
+/*
+Package gitlab implements a backend on GitLab's API.
+
+The project `group/subgroup/project` is the repo with the owner
+`group/subgroup` and the name `project`. Merge requests are the pulls, and
+their number is the merge request's IID.
+
+GitLab can't create blobs, trees and commits separately: they are kept in
+memory until UpdateBranch sends them as a single commit.
+
+Refs:
+- https://docs.gitlab.com/api/rest/
+*/
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/client"
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	// DefaultEndpoint is the API of gitlab.com.
+	DefaultEndpoint = "https://gitlab.com/api/v4"
+
+	apiPath = "/api/v4"
+	perPage = 100
+
+	// headRef resolves to the default branch of a project.
+	headRef = "HEAD"
+)
+
+var _ backend.Backend = (*GitLab)(nil)
+
+type GitLab struct {
+	client   client.Doer
+	Token    string
+	endpoint string
+
+	mu      sync.Mutex
+	count   int
+	blobs   map[string]string
+	trees   map[string]tree
+	commits map[string]commit
+}
+
+func New(c client.Doer, endpoint string) *GitLab {
+	return &GitLab{
+		client:   c,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		blobs:    map[string]string{},
+		trees:    map[string]tree{},
+		commits:  map[string]commit{},
+	}
+}
+
+// projectPath returns the API path of the project, its full path is escaped
+// as GitLab expects.
+func projectPath(r github.Repo) string {
+	return "/projects/" + url.PathEscape(r.Owner.Login+"/"+r.Repo)
+}
+
+// webURL returns the URL of the page of the project.
+func (g *GitLab) webURL(r github.Repo, elem ...string) string {
+	u := strings.TrimSuffix(g.endpoint, apiPath) + "/" + r.Owner.Login + "/" + r.Repo
+	if len(elem) > 0 {
+		u += "/-/" + strings.Join(elem, "/")
+	}
+
+	return u
+}
+
+// req sends the body as JSON and decodes the response in out. The response
+// headers are returned, as some endpoints only answer with them.
+func (g *GitLab) req(ctx context.Context, method, path string, body, out any) (int, http.Header, error) {
+	u := g.endpoint + path
+
+	var r io.Reader
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return http.StatusInternalServerError, nil, fmt.Errorf("%w: %w", github.ErrMarshalRequest, err)
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return http.StatusInternalServerError, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		log.Debug("Request", "method", method, "url", u, "err", err)
+
+		return http.StatusInternalServerError, nil, fmt.Errorf("%w: %w", github.ErrRequestFailed, err)
+	}
+	defer res.Body.Close()
+
+	log.Debug("HTTP", "method", method, "url", u, "status", res.StatusCode)
+
+	code2XX := res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices
+	if !code2XX {
+		return res.StatusCode, res.Header, fmt.Errorf("%w (%s %s): %s", github.ErrRequestFailed, method, u, res.Status)
+	}
+
+	if out != nil && method != http.MethodHead {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return http.StatusInternalServerError, res.Header, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return res.StatusCode, res.Header, nil
+}
+
+// refOrHead returns the ref to read, HEAD if empty.
+func refOrHead(ref string) string {
+	if ref == "" {
+		return headRef
+	}
+
+	return ref
+}
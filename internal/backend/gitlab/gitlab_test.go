@@ -0,0 +1,469 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+const (
+	token   = "token"
+	project = "/api/v4/projects/group%2Fsub%2Fproject"
+)
+
+//nolint:gochecknoglobals // Shared test repository.
+var repo = github.Repo{Owner: github.User{Login: "group/sub"}, Repo: "project", Forge: github.ForgeGitLab}
+
+// setup serves the routes, keyed by `METHOD escaped-path`.
+func setup(t *testing.T, routes map[string]http.HandlerFunc) *GitLab {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			t.Errorf("want the token but got %q", r.Header.Get("Authorization"))
+		}
+
+		if h, ok := routes[r.Method+" "+r.URL.EscapedPath()]; ok {
+			h(w, r)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(ts.Close)
+
+	// NOTE: using http.DefaultClient here is expected, as we mock the server
+	// with ts.
+	g := New(http.DefaultClient, ts.URL+apiPath)
+	g.Token = token
+
+	return g
+}
+
+func respond(t *testing.T, v any) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func decode(t *testing.T, r *http.Request, v any) {
+	t.Helper()
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", b, err)
+	}
+}
+
+func TestGetFile(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/repository/files/dir%2Fb": func(w http.ResponseWriter, r *http.Request) {
+			if ref := r.URL.Query().Get("ref"); ref != "v1" {
+				t.Errorf("want ref v1 but got %q", ref)
+			}
+
+			fmt.Fprint(w, `{"file_name": "b", "size": 7, "encoding": "base64", "content": "Y29udGVudA==", "blob_id": "sha"}`)
+		},
+		"HEAD " + project + "/repository/files/a": func(w http.ResponseWriter, r *http.Request) {
+			if ref := r.URL.Query().Get("ref"); ref != headRef {
+				t.Errorf("want ref %s but got %q", headRef, ref)
+			}
+
+			w.Header().Set("X-Gitlab-Blob-Id", "sha")
+			w.Header().Set("X-Gitlab-Size", "3")
+		},
+	})
+
+	t.Run("reads a file", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo, Path: "dir/b", Ref: "v1"}
+		if err := g.GetFile(t.Context(), &f); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.Content != "content" || f.SHA != "sha" || f.Name != "b" {
+			t.Fatalf("want the file but got %#v", f)
+		}
+
+		if want := g.webURL(repo) + "/-/blob/v1/dir/b"; f.HTMLURL != want {
+			t.Fatalf("want %q but got %q", want, f.HTMLURL)
+		}
+	})
+
+	t.Run("reads a file info", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo, Path: "a"}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.SHA != "sha" || f.Size != 3 || f.Name != "a" {
+			t.Fatalf("want the info but got %#v", f)
+		}
+	})
+
+	t.Run("fails on missing files", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo, Path: "missing"}
+		if err := g.GetFile(t.Context(), &f); !errors.Is(err, github.ErrMissingFile) {
+			t.Fatalf("want %v but got %v", github.ErrMissingFile, err)
+		}
+
+		if err := g.GetFileInfo(t.Context(), &f); !errors.Is(err, github.ErrMissingFile) {
+			t.Fatalf("want %v but got %v", github.ErrMissingFile, err)
+		}
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/repository/tree": respond(t, []map[string]string{
+			{"id": "1", "type": "tree", "path": "dir", "mode": "040000"},
+			{"id": "2", "type": "blob", "path": "dir/b", "mode": "100644"},
+		}),
+	})
+
+	tree, err := g.GetTree(t.Context(), repo, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"dir/b"}; tree.SHA != "main" || !slices.Equal(want, tree.Blobs()) {
+		t.Fatalf("want %v at main but got %#v", want, tree)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	t.Parallel()
+
+	got := []action{}
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/repository/branches/head": respond(t, map[string]any{
+			"name":   "head",
+			"commit": map[string]string{"id": "parent"},
+		}),
+		"HEAD " + project + "/repository/files/a": func(_ http.ResponseWriter, r *http.Request) {
+			if ref := r.URL.Query().Get("ref"); ref != "parent" {
+				t.Errorf("want ref parent but got %q", ref)
+			}
+		},
+		"POST " + project + "/repository/commits": func(w http.ResponseWriter, r *http.Request) {
+			body := struct {
+				Branch   string   `json:"branch"`
+				StartSHA string   `json:"start_sha"`
+				Force    bool     `json:"force"`
+				Message  string   `json:"commit_message"`
+				Actions  []action `json:"actions"`
+			}{}
+			decode(t, r, &body)
+
+			if body.Branch != "head" || body.StartSHA != "parent" || !body.Force || body.Message != "message" {
+				t.Errorf("want a commit on head from parent but got %#v", body)
+			}
+
+			got = body.Actions
+
+			fmt.Fprint(w, `{"id": "commit"}`)
+		},
+	})
+
+	ctx := t.Context()
+
+	blob, err := g.CreateBlob(ctx, repo, "content")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := g.CreateTree(ctx, repo, "parent", []github.TreeEntry{
+		{Path: "a", Mode: github.ModeFile, Type: github.TypeBlob, SHA: blob},
+		{Path: "c", Mode: modeExecutable, Type: github.TypeBlob, SHA: blob},
+		{Path: "a/deleted", Mode: github.ModeFile, Type: github.TypeBlob},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := g.CreateCommit(ctx, repo, "message", tree.SHA, []string{"parent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := g.UpdateBranch(ctx, repo, "head", commit.SHA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Name != "head" || b.Commit.SHA != "commit" {
+		t.Fatalf("want head on the commit but got %#v", b)
+	}
+
+	want := []action{
+		{Action: actionUpdate, FilePath: "a", Content: "Y29udGVudA==", Encoding: encodingBase64},
+		{Action: actionCreate, FilePath: "c", Content: "Y29udGVudA==", Encoding: encodingBase64, ExecuteFilemode: true},
+	}
+
+	if !slices.Equal(want, got) {
+		t.Fatalf("want %#v but got %#v", want, got)
+	}
+
+	if _, err := g.UpdateBranch(ctx, repo, "head", commit.SHA); !errors.Is(err, errPendingCommit) {
+		t.Fatalf("want %v but got %v", errPendingCommit, err)
+	}
+
+	tree, err = g.CreateTree(ctx, repo, "other", []github.TreeEntry{{Path: "a", Mode: github.ModeFile, SHA: blob}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := g.CreateCommit(ctx, repo, "message", tree.SHA, []string{"other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.UpdateBranch(ctx, repo, "head", moved.SHA); !errors.Is(err, errBranchMoved) {
+		t.Fatalf("want %v but got %v", errBranchMoved, err)
+	}
+}
+
+func TestResetBranch(t *testing.T) {
+	t.Parallel()
+
+	for name, fail := range map[string]bool{"moves the branch": false, "restores the branch": true} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			created := []string{}
+
+			g := setup(t, map[string]http.HandlerFunc{
+				"GET " + project + "/repository/branches/head": respond(t, map[string]any{
+					"name":   "head",
+					"commit": map[string]string{"id": "old"},
+				}),
+				"DELETE " + project + "/repository/branches/head": func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+				"POST " + project + "/repository/branches": func(w http.ResponseWriter, r *http.Request) {
+					body := map[string]string{}
+					decode(t, r, &body)
+
+					created = append(created, body["ref"])
+
+					if fail && body["ref"] == "new" {
+						w.WriteHeader(http.StatusInternalServerError)
+
+						return
+					}
+
+					w.WriteHeader(http.StatusCreated)
+				},
+			})
+
+			b, err := g.ResetBranch(t.Context(), repo, "head", "new")
+
+			want := []string{"new"}
+			if fail {
+				want = append(want, "old")
+
+				if !errors.Is(err, github.ErrResetBranch) {
+					t.Fatalf("want %v but got %v", github.ErrResetBranch, err)
+				}
+			} else if err != nil || b.Commit.SHA != "new" || b.New {
+				t.Fatalf("want the branch on new but got %#v, %v", b, err)
+			}
+
+			if !slices.Equal(want, created) {
+				t.Fatalf("want the branch created on %v but got %v", want, created)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/repository/compare": func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("from") {
+			case "base":
+				fmt.Fprint(w, `{"commits": [{}, {}]}`)
+			default:
+				fmt.Fprint(w, `{"commits": [{}]}`)
+			}
+		},
+	})
+
+	c, err := g.Compare(t.Context(), repo, "base", "head")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := github.Comparison{Status: github.CompareDiverged, AheadBy: 2, BehindBy: 1}
+	if c != want {
+		t.Fatalf("want %#v but got %#v", want, c)
+	}
+}
+
+func TestGetOrCreatePull(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gets the open merge request", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, map[string]http.HandlerFunc{
+			"GET " + project + "/merge_requests": respond(t, []mergeRequest{
+				{IID: 2, WebURL: "url", MergeWhenPipelineSucceeds: true},
+			}),
+		})
+
+		p, err := g.GetOrCreatePull(t.Context(), repo, "base", "head", "title", "body", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.New || p.Number != 2 || p.String() != "url" || p.AutoMerge == nil {
+			t.Fatalf("want the existing pull but got %#v", p)
+		}
+	})
+
+	t.Run("creates a draft", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, map[string]http.HandlerFunc{
+			"GET " + project + "/merge_requests": respond(t, []mergeRequest{}),
+			"POST " + project + "/merge_requests": func(w http.ResponseWriter, r *http.Request) {
+				body := map[string]string{}
+				decode(t, r, &body)
+
+				if body["title"] != draftPrefix+"title" || body["source_branch"] != "head" {
+					t.Errorf("want a draft from head but got %v", body)
+				}
+
+				fmt.Fprint(w, `{"iid": 3, "web_url": "url"}`)
+			},
+		})
+
+		p, err := g.GetOrCreatePull(t.Context(), repo, "base", "head", "title", "body", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !p.New || p.Number != 3 || !p.Repo.Equal(repo) {
+			t.Fatalf("want a new pull but got %#v", p)
+		}
+	})
+}
+
+func TestConvertToDraft(t *testing.T) {
+	t.Parallel()
+
+	got := map[string]string{}
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/merge_requests/1": respond(t, mergeRequest{Title: "title"}),
+		"PUT " + project + "/merge_requests/1": func(_ http.ResponseWriter, r *http.Request) {
+			decode(t, r, &got)
+		},
+	})
+
+	if err := g.ConvertToDraft(t.Context(), github.Pull{Number: 1, Repo: repo}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := draftPrefix + "title"; got["title"] != want {
+		t.Fatalf("want %q but got %v", want, got)
+	}
+}
+
+func TestMergePull(t *testing.T) {
+	t.Parallel()
+
+	p := github.Pull{Number: 1, Repo: repo}
+
+	for status, want := range map[int]error{
+		http.StatusOK:                  nil,
+		http.StatusMethodNotAllowed:    github.ErrPullNotMergeable,
+		http.StatusInternalServerError: github.ErrMergePull,
+	} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			t.Parallel()
+
+			g := setup(t, map[string]http.HandlerFunc{
+				"PUT " + project + "/merge_requests/1/merge": func(w http.ResponseWriter, r *http.Request) {
+					opts := mergeOptions{}
+					decode(t, r, &opts)
+
+					if !opts.Squash {
+						t.Errorf("want a squash but got %#v", opts)
+					}
+
+					w.WriteHeader(status)
+				},
+			})
+
+			if err := g.MergePull(t.Context(), p, methodSquash); !errors.Is(err, want) {
+				t.Fatalf("want %v but got %v", want, err)
+			}
+		})
+	}
+}
+
+func TestAddAssignees(t *testing.T) {
+	t.Parallel()
+
+	got := map[string][]int{}
+
+	g := setup(t, map[string]http.HandlerFunc{
+		"GET " + project + "/merge_requests/1": respond(t, mergeRequest{Assignees: []user{{ID: 1}}}),
+		"GET /api/v4/users": func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("username") {
+			case "a":
+				fmt.Fprint(w, `[{"id": 1}]`)
+			case "b":
+				fmt.Fprint(w, `[{"id": 2}]`)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		},
+		"PUT " + project + "/merge_requests/1": func(_ http.ResponseWriter, r *http.Request) {
+			decode(t, r, &got)
+		},
+	})
+
+	p := github.Pull{Number: 1, Repo: repo}
+
+	if err := g.AddAssignees(t.Context(), p, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{1, 2}; !slices.Equal(want, got["assignee_ids"]) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+
+	if err := g.AddAssignees(t.Context(), p, []string{"missing"}); !errors.Is(err, errUnknownUser) {
+		t.Fatalf("want %v but got %v", errUnknownUser, err)
+	}
+}
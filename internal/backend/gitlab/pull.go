@@ -0,0 +1,400 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	draftPrefix  = "Draft: "
+	methodSquash = "squash"
+)
+
+var errUnknownUser = errors.New("unknown user")
+
+type user struct {
+	ID int `json:"id"`
+}
+
+type mergeRequest struct {
+	IID                       int    `json:"iid"`
+	Title                     string `json:"title"`
+	WebURL                    string `json:"web_url"`
+	Draft                     bool   `json:"draft"`
+	MergeWhenPipelineSucceeds bool   `json:"merge_when_pipeline_succeeds"`
+	Assignees                 []user `json:"assignees"`
+	Reviewers                 []user `json:"reviewers"`
+}
+
+func (m mergeRequest) pull(r github.Repo) github.Pull {
+	p := github.Pull{Number: m.IID, HTMLURL: m.WebURL, Draft: m.Draft, Repo: r}
+
+	if m.MergeWhenPipelineSucceeds {
+		p.AutoMerge = &struct {
+			MergeMethod string `json:"merge_method"`
+		}{}
+	}
+
+	return p
+}
+
+func pullPath(p github.Pull, elem ...string) string {
+	return strings.Join(append([]string{projectPath(p.Repo), "merge_requests", strconv.Itoa(p.Number)}, elem...), "/")
+}
+
+// https://docs.gitlab.com/api/merge_requests/#list-project-merge-requests
+func (g *GitLab) GetPull(ctx context.Context, r github.Repo, base, head string) (github.Pull, error) {
+	q := url.Values{
+		"state":         []string{"opened"},
+		"target_branch": []string{base},
+		"source_branch": []string{head},
+	}
+
+	mrs := []mergeRequest{}
+
+	p := fmt.Sprintf("%s/merge_requests?%s", projectPath(r), q.Encode())
+	if _, _, err := g.req(ctx, http.MethodGet, p, nil, &mrs); err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrGetPull, err)
+	}
+
+	if len(mrs) == 0 {
+		return github.Pull{}, github.ErrNoPull
+	}
+
+	return mrs[0].pull(r), nil
+}
+
+// CreatePull opens a merge request, a draft's title is prefixed with `Draft: `.
+// https://docs.gitlab.com/api/merge_requests/#create-mr
+func (g *GitLab) CreatePull(
+	ctx context.Context,
+	r github.Repo,
+	base, head, title, body string,
+	draft bool,
+) (github.Pull, error) {
+	if draft {
+		title = draftPrefix + title
+	}
+
+	req := struct {
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+	}{
+		SourceBranch: head,
+		TargetBranch: base,
+		Title:        title,
+		Description:  body,
+	}
+
+	mr := mergeRequest{}
+
+	status, _, err := g.req(ctx, http.MethodPost, projectPath(r)+"/merge_requests", req, &mr)
+	if status == http.StatusConflict {
+		return github.Pull{}, github.ErrPullExists
+	}
+
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrCreatePull, err)
+	}
+
+	p := mr.pull(r)
+	p.New = true
+
+	return p, nil
+}
+
+func (g *GitLab) GetOrCreatePull(
+	ctx context.Context,
+	r github.Repo,
+	base, head, title, body string,
+	draft bool,
+) (github.Pull, error) {
+	p, err := g.GetPull(ctx, r, base, head)
+	if err == nil {
+		return p, nil
+	}
+
+	if !errors.Is(err, github.ErrNoPull) {
+		return github.Pull{}, err
+	}
+
+	return g.CreatePull(ctx, r, base, head, title, body, draft)
+}
+
+// getMergeRequest returns the merge request of the pull.
+// https://docs.gitlab.com/api/merge_requests/#get-single-mr
+func (g *GitLab) getMergeRequest(ctx context.Context, p github.Pull) (mergeRequest, error) {
+	mr := mergeRequest{}
+
+	if _, _, err := g.req(ctx, http.MethodGet, pullPath(p), nil, &mr); err != nil {
+		return mergeRequest{}, err
+	}
+
+	return mr, nil
+}
+
+// updateMergeRequest sends the changed attributes of the pull.
+// https://docs.gitlab.com/api/merge_requests/#update-mr
+func (g *GitLab) updateMergeRequest(ctx context.Context, p github.Pull, body any) error {
+	_, _, err := g.req(ctx, http.MethodPut, pullPath(p), body, nil)
+
+	return err
+}
+
+// UpdatePull keeps the draft prefix, as it is part of the title.
+func (g *GitLab) UpdatePull(ctx context.Context, p github.Pull, title, body string) (github.Pull, error) {
+	mr, err := g.getMergeRequest(ctx, p)
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrUpdatePull, err)
+	}
+
+	if mr.Draft {
+		title = draftPrefix + title
+	}
+
+	err = g.updateMergeRequest(ctx, p, struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}{
+		Title:       title,
+		Description: body,
+	})
+	if err != nil {
+		return github.Pull{}, fmt.Errorf("%w: %w", github.ErrUpdatePull, err)
+	}
+
+	return p, nil
+}
+
+// ConvertToDraft prefixes the title with `Draft: `.
+func (g *GitLab) ConvertToDraft(ctx context.Context, p github.Pull) error {
+	mr, err := g.getMergeRequest(ctx, p)
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrConvertToDraft, err)
+	}
+
+	if mr.Draft {
+		return nil
+	}
+
+	err = g.updateMergeRequest(ctx, p, struct {
+		Title string `json:"title"`
+	}{
+		Title: draftPrefix + mr.Title,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrConvertToDraft, err)
+	}
+
+	return nil
+}
+
+func (g *GitLab) ClosePull(ctx context.Context, p github.Pull) error {
+	err := g.updateMergeRequest(ctx, p, struct {
+		StateEvent string `json:"state_event"`
+	}{
+		StateEvent: "close",
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrClosePull, err)
+	}
+
+	return nil
+}
+
+type mergeOptions struct {
+	Squash                    bool `json:"squash"`
+	AutoMerge                 bool `json:"auto_merge,omitempty"`
+	MergeWhenPipelineSucceeds bool `json:"merge_when_pipeline_succeeds,omitempty"`
+}
+
+// notMergeable returns true for the statuses GitLab uses when the merge is
+// blocked: by checks, conflicts or a moved head.
+func notMergeable(status int) bool {
+	return slices.Contains([]int{
+		http.StatusMethodNotAllowed,
+		http.StatusNotAcceptable,
+		http.StatusConflict,
+		http.StatusUnprocessableEntity,
+	}, status)
+}
+
+// MergePull merges the merge request. Only squash changes how it's merged,
+// merge and rebase use the project's merge method.
+// https://docs.gitlab.com/api/merge_requests/#merge-a-merge-request
+func (g *GitLab) MergePull(ctx context.Context, p github.Pull, method string) error {
+	status, _, err := g.req(ctx, http.MethodPut, pullPath(p, "merge"), mergeOptions{Squash: method == methodSquash}, nil)
+	if err != nil {
+		if notMergeable(status) {
+			return fmt.Errorf("%w: %w", github.ErrPullNotMergeable, err)
+		}
+
+		return fmt.Errorf("%w: %w", github.ErrMergePull, err)
+	}
+
+	return nil
+}
+
+// EnableAutoMerge merges the merge request once its pipeline succeeds.
+func (g *GitLab) EnableAutoMerge(ctx context.Context, p github.Pull, method string) error {
+	opts := mergeOptions{
+		Squash:                    method == methodSquash,
+		AutoMerge:                 true,
+		MergeWhenPipelineSucceeds: true,
+	}
+
+	if _, _, err := g.req(ctx, http.MethodPut, pullPath(p, "merge"), opts, nil); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrEnableAutoMerge, err)
+	}
+
+	return nil
+}
+
+// https://docs.gitlab.com/api/notes/#create-new-merge-request-note
+func (g *GitLab) AddComment(ctx context.Context, p github.Pull, comment string) error {
+	body := struct {
+		Body string `json:"body"`
+	}{
+		Body: comment,
+	}
+
+	if _, _, err := g.req(ctx, http.MethodPost, pullPath(p, "notes"), body, nil); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddComment, err)
+	}
+
+	return nil
+}
+
+func (g *GitLab) AddLabels(ctx context.Context, p github.Pull, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	err := g.updateMergeRequest(ctx, p, struct {
+		AddLabels string `json:"add_labels"`
+	}{
+		AddLabels: strings.Join(labels, ","),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddLabels, err)
+	}
+
+	return nil
+}
+
+// AddAssignees adds the users to the current assignees, as GitLab replaces
+// them.
+func (g *GitLab) AddAssignees(ctx context.Context, p github.Pull, assignees []string) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	if err := g.addUsers(ctx, p, assignees, "assignee_ids", func(mr mergeRequest) []user {
+		return mr.Assignees
+	}); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrAddAssignees, err)
+	}
+
+	return nil
+}
+
+// RequestReviewers adds the users to the current reviewers, as GitLab replaces
+// them. GitLab can't request a review from a group, so teams are ignored.
+func (g *GitLab) RequestReviewers(ctx context.Context, p github.Pull, reviewers, teams []string) error {
+	if len(teams) > 0 {
+		log.Warn("GitLab does not support team reviewers", "pull", p, "teams", teams)
+	}
+
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	if err := g.addUsers(ctx, p, reviewers, "reviewer_ids", func(mr mergeRequest) []user {
+		return mr.Reviewers
+	}); err != nil {
+		return fmt.Errorf("%w: %w", github.ErrRequestReviewers, err)
+	}
+
+	return nil
+}
+
+// addUsers sets the attribute to the current users and the new ones.
+func (g *GitLab) addUsers(
+	ctx context.Context,
+	p github.Pull,
+	usernames []string,
+	attribute string,
+	current func(mergeRequest) []user,
+) error {
+	ids := []int{}
+
+	// NOTE: A new merge request has no users yet.
+	if !p.New {
+		mr, err := g.getMergeRequest(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range current(mr) {
+			ids = append(ids, u.ID)
+		}
+	}
+
+	for _, name := range usernames {
+		id, err := g.userID(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return g.updateMergeRequest(ctx, p, map[string][]int{attribute: ids})
+}
+
+// https://docs.gitlab.com/api/users/#list-users
+func (g *GitLab) userID(ctx context.Context, username string) (int, error) {
+	users := []user{}
+
+	p := "/users?" + url.Values{"username": []string{username}}.Encode()
+	if _, _, err := g.req(ctx, http.MethodGet, p, nil, &users); err != nil {
+		return 0, err
+	}
+
+	if len(users) == 0 {
+		return 0, fmt.Errorf("%w: %s", errUnknownUser, username)
+	}
+
+	return users[0].ID, nil
+}
+
+// SetMilestone sets the milestone, GitLab expects its global ID.
+func (g *GitLab) SetMilestone(ctx context.Context, p github.Pull, milestone int) error {
+	if milestone == 0 {
+		return nil
+	}
+
+	err := g.updateMergeRequest(ctx, p, struct {
+		MilestoneID int `json:"milestone_id"`
+	}{
+		MilestoneID: milestone,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", github.ErrSetMilestone, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+var ErrUnknownForge = errors.New("no backend for the forge")
+
+var _ Backend = (*Router)(nil)
+
+// Router sends each call to the backend of the repo's forge, so a link can go
+// from one forge to another.
+type Router struct {
+	main   Backend
+	forges map[string]Backend
+}
+
+// NewRouter returns a router sending the repos without a forge to main.
+func NewRouter(main Backend) *Router {
+	return &Router{main: main, forges: map[string]Backend{}}
+}
+
+// Add sends the repos of the forge to b.
+func (rt *Router) Add(forge string, b Backend) {
+	rt.forges[forge] = b
+}
+
+func (rt *Router) backend(repo github.Repo) (Backend, error) {
+	if repo.Forge == "" {
+		return rt.main, nil
+	}
+
+	if b, ok := rt.forges[repo.Forge]; ok {
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("%w %q: %s", ErrUnknownForge, repo.Forge, repo)
+}
+
+func (rt *Router) GetFile(ctx context.Context, f *github.File) error {
+	b, err := rt.backend(f.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.GetFile(ctx, f)
+}
+
+func (rt *Router) GetFileInfo(ctx context.Context, f *github.File) error {
+	b, err := rt.backend(f.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.GetFileInfo(ctx, f)
+}
+
+func (rt *Router) GetRepo(ctx context.Context, r *github.Repo) error {
+	b, err := rt.backend(*r)
+	if err != nil {
+		return err
+	}
+
+	return b.GetRepo(ctx, r)
+}
+
+func (rt *Router) GetTree(ctx context.Context, r github.Repo, ref string) (github.Tree, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Tree{}, err
+	}
+
+	return b.GetTree(ctx, r, ref)
+}
+
+func (rt *Router) GetTags(ctx context.Context, r github.Repo) ([]github.Tag, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.GetTags(ctx, r)
+}
+
+func (rt *Router) GetLatestRelease(ctx context.Context, r github.Repo) (github.Release, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Release{}, err
+	}
+
+	return b.GetLatestRelease(ctx, r)
+}
+
+func (rt *Router) GetCommits(
+	ctx context.Context,
+	r github.Repo,
+	ref, path string,
+	limit int,
+) ([]github.RepoCommit, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.GetCommits(ctx, r, ref, path, limit)
+}
+
+func (rt *Router) CreateBlob(ctx context.Context, r github.Repo, content string) (string, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return "", err
+	}
+
+	return b.CreateBlob(ctx, r, content)
+}
+
+func (rt *Router) CreateTree(
+	ctx context.Context,
+	r github.Repo,
+	baseTree string,
+	entries []github.TreeEntry,
+) (github.Tree, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Tree{}, err
+	}
+
+	return b.CreateTree(ctx, r, baseTree, entries)
+}
+
+func (rt *Router) CreateCommit(
+	ctx context.Context,
+	r github.Repo,
+	msg, tree string,
+	parents []string,
+) (github.Commit, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Commit{}, err
+	}
+
+	return b.CreateCommit(ctx, r, msg, tree, parents)
+}
+
+func (rt *Router) UpdateBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	return b.UpdateBranch(ctx, r, name, sha)
+}
+
+func (rt *Router) GetDefaultBranch(ctx context.Context, r github.Repo) (github.Branch, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	return b.GetDefaultBranch(ctx, r)
+}
+
+func (rt *Router) GetDefaultBranchName(ctx context.Context, r github.Repo) (string, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return "", err
+	}
+
+	return b.GetDefaultBranchName(ctx, r)
+}
+
+func (rt *Router) GetBaseAndHeadBranches(ctx context.Context, r github.Repo, headName string) (
+	base github.Branch, head github.Branch,
+	err error,
+) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return base, head, err
+	}
+
+	return b.GetBaseAndHeadBranches(ctx, r, headName)
+}
+
+func (rt *Router) DeleteBranch(ctx context.Context, r github.Repo, name string) error {
+	b, err := rt.backend(r)
+	if err != nil {
+		return err
+	}
+
+	return b.DeleteBranch(ctx, r, name)
+}
+
+func (rt *Router) ResetBranch(ctx context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Branch{}, err
+	}
+
+	return b.ResetBranch(ctx, r, name, sha)
+}
+
+func (rt *Router) Compare(ctx context.Context, r github.Repo, base, head string) (github.Comparison, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Comparison{}, err
+	}
+
+	return b.Compare(ctx, r, base, head)
+}
+
+func (rt *Router) GetPull(ctx context.Context, r github.Repo, base, head string) (github.Pull, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Pull{}, err
+	}
+
+	return b.GetPull(ctx, r, base, head)
+}
+
+func (rt *Router) GetOrCreatePull(
+	ctx context.Context,
+	r github.Repo,
+	base, head, title, body string,
+	draft bool,
+) (github.Pull, error) {
+	b, err := rt.backend(r)
+	if err != nil {
+		return github.Pull{}, err
+	}
+
+	return b.GetOrCreatePull(ctx, r, base, head, title, body, draft)
+}
+
+func (rt *Router) UpdatePull(ctx context.Context, p github.Pull, title, body string) (github.Pull, error) {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return github.Pull{}, err
+	}
+
+	return b.UpdatePull(ctx, p, title, body)
+}
+
+func (rt *Router) ClosePull(ctx context.Context, p github.Pull) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.ClosePull(ctx, p)
+}
+
+func (rt *Router) MergePull(ctx context.Context, p github.Pull, method string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.MergePull(ctx, p, method)
+}
+
+func (rt *Router) ConvertToDraft(ctx context.Context, p github.Pull) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.ConvertToDraft(ctx, p)
+}
+
+func (rt *Router) EnableAutoMerge(ctx context.Context, p github.Pull, method string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.EnableAutoMerge(ctx, p, method)
+}
+
+func (rt *Router) AddComment(ctx context.Context, p github.Pull, comment string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.AddComment(ctx, p, comment)
+}
+
+func (rt *Router) AddLabels(ctx context.Context, p github.Pull, labels []string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.AddLabels(ctx, p, labels)
+}
+
+func (rt *Router) AddAssignees(ctx context.Context, p github.Pull, assignees []string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.AddAssignees(ctx, p, assignees)
+}
+
+func (rt *Router) RequestReviewers(ctx context.Context, p github.Pull, reviewers, teams []string) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.RequestReviewers(ctx, p, reviewers, teams)
+}
+
+func (rt *Router) SetMilestone(ctx context.Context, p github.Pull, milestone int) error {
+	b, err := rt.backend(p.Repo)
+	if err != nil {
+		return err
+	}
+
+	return b.SetMilestone(ctx, p, milestone)
+}
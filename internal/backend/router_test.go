@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+// serve returns a GitHub backend whose repos all have the default branch.
+func serve(t *testing.T, branch string) *github.GitHub {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"default_branch": %q}`, branch)
+	}))
+	t.Cleanup(ts.Close)
+
+	return github.New(http.DefaultClient, ts.URL)
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	r := NewRouter(serve(t, "main"))
+	r.Add(github.ForgeGitLab, serve(t, "gitlab"))
+
+	for forge, want := range map[string]string{"": "main", github.ForgeGitLab: "gitlab"} {
+		repo := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo", Forge: forge}
+
+		got, err := r.GetDefaultBranchName(t.Context(), repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Fatalf("want %q for %s but got %q", want, repo, got)
+		}
+	}
+
+	repo := github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo", Forge: "unknown"}
+	if _, err := r.GetDefaultBranchName(t.Context(), repo); !errors.Is(err, ErrUnknownForge) {
+		t.Fatalf("want %v but got %v", ErrUnknownForge, err)
+	}
+}
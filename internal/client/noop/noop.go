@@ -20,8 +20,8 @@ func New() Client {
 }
 
 func (c Client) Do(req *http.Request) (*http.Response, error) {
-	if req.Method == http.MethodGet {
-		//nolint:wrapcheck // Get requests can be transparent.
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		//nolint:wrapcheck // Get and head requests can be transparent.
 		return c.fallback.Do(req)
 	}
 
@@ -42,16 +42,106 @@ func (c Client) noopPost(req *http.Request) (*http.Response, error) {
 		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs").MatchString(req.URL.Path):
 		return response(http.StatusCreated, `{}`), nil
 
-	// github.UpdateFile
+	// github.CreateBlob
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/blobs").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_blob_sha"}`), nil
+
+	// github.CreateTree
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/trees").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_tree_sha"}`), nil
+
+	// github.CreateCommit
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/commits").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"sha":"noop_commit_sha"}`), nil
+
+	// github.DeleteBranch
+	case req.Method == http.MethodDelete &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs/heads/.+").MatchString(req.URL.Path):
+		return response(http.StatusNoContent, ``), nil
+
+	// github.UpdateBranch
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/git/refs/heads/.+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// github.RequestReviewers
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls/[^/]+/requested_reviewers").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
+
+	// github.MergePull
 	case req.Method == http.MethodPut &&
-		regexp.MustCompile("/repos/[^/]+/[^/]+/contents/.+").MatchString(req.URL.Path):
-		return response(http.StatusOK, `{"sha":"noop_sha_1234"}`), nil
+		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls/[^/]+/merge").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{"merged":true}`), nil
+
+	// github.EnableAutoMerge
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/graphql$").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{"data":{}}`), nil
+
+	// github.UpdatePull, github.ClosePull
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls/[^/]+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
 
 	// github.CreatePull
 	case req.Method == http.MethodPost &&
 		regexp.MustCompile("/repos/[^/]+/[^/]+/pulls").MatchString(req.URL.Path):
 		return response(http.StatusOK, `{"number": -1}`), nil
 
+	// github.AddLabels, github.AddAssignees
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+/(labels|assignees)").MatchString(req.URL.Path):
+		return response(http.StatusOK, `[]`), nil
+
+	// github.AddComment
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+/comments").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
+
+	// github.SetMilestone
+	case req.Method == http.MethodPatch &&
+		regexp.MustCompile("/repos/[^/]+/[^/]+/issues/[^/]+").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// gitlab.CreateBranch
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/projects/.+/repository/branches$").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
+
+	// gitlab.DeleteBranch
+	case req.Method == http.MethodDelete &&
+		regexp.MustCompile("/projects/.+/repository/branches/.+").MatchString(req.URL.Path):
+		return response(http.StatusNoContent, ``), nil
+
+	// gitlab.UpdateBranch
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/projects/.+/repository/commits$").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"id":"noop_commit_sha"}`), nil
+
+	// gitlab.MergePull, gitlab.EnableAutoMerge
+	case req.Method == http.MethodPut &&
+		regexp.MustCompile("/projects/.+/merge_requests/[^/]+/merge$").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// gitlab.UpdatePull, gitlab.ClosePull, gitlab.AddLabels, ...
+	case req.Method == http.MethodPut &&
+		regexp.MustCompile("/projects/.+/merge_requests/[^/]+$").MatchString(req.URL.Path):
+		return response(http.StatusOK, `{}`), nil
+
+	// gitlab.AddComment
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/projects/.+/merge_requests/[^/]+/notes$").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{}`), nil
+
+	// gitlab.CreatePull
+	case req.Method == http.MethodPost &&
+		regexp.MustCompile("/projects/.+/merge_requests$").MatchString(req.URL.Path):
+		return response(http.StatusCreated, `{"iid": -1}`), nil
+
 	default:
 		return response(http.StatusBadRequest, ""),
 			fmt.Errorf("%w: %s path %s", errors.ErrUnsupported, req.Method, req.URL.Path)
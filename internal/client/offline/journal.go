@@ -0,0 +1,74 @@
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Entry is a write request received by the client.
+type Entry struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Journal records the writes in memory, and appends them as JSON lines to
+// path, if set.
+type Journal struct {
+	path    string
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Entries returns a copy of the recorded writes.
+func (j *Journal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]Entry{}, j.entries...)
+}
+
+func (j *Journal) add(e Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, e)
+
+	if j.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errJournal, err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errJournal, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%w: %w", errJournal, err)
+	}
+
+	return nil
+}
+
+// rawBody keeps JSON bodies as-is, and quotes the others.
+func rawBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if json.Valid(body) {
+		return body
+	}
+
+	quoted, _ := json.Marshal(string(body)) //nolint:errchkjson // Strings always marshal.
+
+	return quoted
+}
@@ -0,0 +1,532 @@
+/*
+Package offline implements a client that serves the GitHub API from a local
+directory, without any network or token.
+
+The directory contains one folder per repository and ref, e.g.:
+
+	owner/repo/HEAD            # Name of the default branch, `main` if missing.
+	owner/repo/main/.ln-config.yaml
+	owner/repo/v1.2.3/path/to/file
+
+Writes are not applied, but recorded in a Journal. Branches created or reset
+on a known commit point to the same folder.
+*/
+package offline
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // Only used to fake commit hashes.
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nobe4/action-ln/internal/client/noop"
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	defaultBranch = "main"
+	headFile      = "HEAD"
+	headRef       = "HEAD"
+)
+
+var (
+	errOutsideRoot = errors.New("path outside of the root")
+	errJournal     = errors.New("failed to write the journal")
+	errGitLab      = errors.New("GitLab is not supported offline")
+)
+
+var (
+	repoPattern     = regexp.MustCompile(`/repos/([^/]+)/([^/]+)$`)
+	contentsPattern = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/contents/?(.*)$`)
+	branchPattern   = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/branches/(.+)$`)
+	treePattern     = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/git/trees/(.+)$`)
+	tagsPattern     = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/tags$`)
+	listPattern     = regexp.MustCompile(`/repos/[^/]+/[^/]+/(pulls|commits)$`)
+	comparePattern  = regexp.MustCompile(`/repos/[^/]+/[^/]+/compare/.+$`)
+	notFoundPattern = regexp.MustCompile(`/repos/[^/]+/[^/]+/(releases/latest|git/blobs/.+)$`)
+
+	createRefPattern = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/git/refs$`)
+	refPattern       = regexp.MustCompile(`/repos/([^/]+)/([^/]+)/git/refs/heads/(.+)$`)
+	tokenPattern     = regexp.MustCompile(`/app/installations/[^/]+/access_tokens$`)
+
+	gitLabPattern = regexp.MustCompile(`^(/api/v4)?/(projects|users)(/|$)`)
+)
+
+type Client struct {
+	root    string
+	journal *Journal
+	writer  noop.Client
+
+	mu sync.Mutex
+	// branches maps `owner/repo@branch` to the folder it points to.
+	branches map[string]string
+}
+
+// New returns a client serving root. The journal is also appended to
+// journalPath, if set.
+func New(root, journalPath string) *Client {
+	return &Client{
+		root:     root,
+		journal:  &Journal{path: journalPath},
+		writer:   noop.New(),
+		branches: map[string]string{},
+	}
+}
+
+// Journal returns the writes received so far.
+func (c *Client) Journal() []Entry {
+	return c.journal.Entries()
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if gitLabPattern.MatchString(req.URL.Path) {
+		return response(http.StatusBadRequest, ""),
+			fmt.Errorf("%w: %s path %s", errGitLab, req.Method, req.URL.Path)
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return c.get(req)
+
+	// NOTE: noop.Client forwards the reads, so they must never reach it.
+	case http.MethodHead:
+		return response(http.StatusBadRequest, ""),
+			fmt.Errorf("%w: %s path %s", errors.ErrUnsupported, req.Method, req.URL.Path)
+
+	default:
+		return c.write(req)
+	}
+}
+
+//nolint:cyclop // Each route is simple.
+func (c *Client) get(req *http.Request) (*http.Response, error) {
+	p := req.URL.Path
+	q := req.URL.Query()
+
+	log.Debug("[OFFLINE] HTTP", "method", req.Method, "path", p)
+
+	if m := repoPattern.FindStringSubmatch(p); m != nil {
+		return c.repo(m[1], m[2])
+	}
+
+	if m := contentsPattern.FindStringSubmatch(p); m != nil {
+		return c.contents(m[1], m[2], q.Get("ref"), m[3])
+	}
+
+	if m := branchPattern.FindStringSubmatch(p); m != nil {
+		return c.branch(m[1], m[2], m[3])
+	}
+
+	if m := treePattern.FindStringSubmatch(p); m != nil {
+		return c.tree(m[1], m[2], m[3])
+	}
+
+	if m := tagsPattern.FindStringSubmatch(p); m != nil {
+		return c.tags(m[1], m[2], q.Get("page"))
+	}
+
+	switch {
+	// github.GetPull, github.GetCommits
+	case listPattern.MatchString(p):
+		return jsonResponse(http.StatusOK, []any{})
+
+	// github.Compare
+	case comparePattern.MatchString(p):
+		return jsonResponse(http.StatusOK, github.Comparison{Status: github.CompareIdentical})
+
+	// github.GetLatestRelease, github.GetBlob
+	case notFoundPattern.MatchString(p):
+		return response(http.StatusNotFound, ""), nil
+
+	default:
+		return response(http.StatusBadRequest, ""),
+			fmt.Errorf("%w: %s path %s", errors.ErrUnsupported, req.Method, p)
+	}
+}
+
+// write records the request in the journal, and answers like noop.Client.
+func (c *Client) write(req *http.Request) (*http.Response, error) {
+	p := req.URL.Path
+
+	// github.Auth
+	if tokenPattern.MatchString(p) {
+		return jsonResponse(http.StatusCreated, github.AppToken{Token: "offline_token"})
+	}
+
+	body := []byte{}
+
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return response(http.StatusBadRequest, ""), fmt.Errorf("failed to read the body: %w", err)
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if err := c.journal.add(Entry{Method: req.Method, Path: p, Body: rawBody(body)}); err != nil {
+		return response(http.StatusInternalServerError, ""), err
+	}
+
+	c.moveBranch(req.Method, p, body)
+
+	return c.writer.Do(req) //nolint:wrapcheck // The noop client must be transparent.
+}
+
+// moveBranch points a created or updated branch to the folder of its commit,
+// if it's one this client served.
+func (c *Client) moveBranch(method, p string, body []byte) {
+	ref := struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}{}
+
+	var owner, repo, name string
+
+	if m := createRefPattern.FindStringSubmatch(p); m != nil && method == http.MethodPost {
+		if err := json.Unmarshal(body, &ref); err != nil {
+			return
+		}
+
+		owner, repo, name = m[1], m[2], strings.TrimPrefix(ref.Ref, "refs/heads/")
+	} else if m := refPattern.FindStringSubmatch(p); m != nil {
+		owner, repo, name = m[1], m[2], m[3]
+
+		if method == http.MethodDelete {
+			c.mu.Lock()
+			delete(c.branches, owner+"/"+repo+"@"+name)
+			c.mu.Unlock()
+
+			return
+		}
+
+		if err := json.Unmarshal(body, &ref); err != nil {
+			return
+		}
+	} else {
+		return
+	}
+
+	if folder, ok := c.commitFolder(owner, repo, ref.SHA); ok {
+		c.mu.Lock()
+		c.branches[owner+"/"+repo+"@"+name] = folder
+		c.mu.Unlock()
+	}
+}
+
+// commitFolder returns the folder whose fake commit hash is sha.
+func (c *Client) commitFolder(owner, repo, sha string) (string, bool) {
+	refs, err := c.refs(owner, repo)
+	if err != nil {
+		return "", false
+	}
+
+	for _, r := range refs {
+		if commitSHA(owner, repo, r) == sha {
+			return r, true
+		}
+	}
+
+	return "", false
+}
+
+// repo serves github.GetRepo.
+func (c *Client) repo(owner, repo string) (*http.Response, error) {
+	dir, err := c.path(owner, repo)
+	if err != nil {
+		return response(http.StatusBadRequest, ""), err
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return response(http.StatusNotFound, ""), nil
+	}
+
+	return jsonResponse(http.StatusOK, github.Repo{
+		Owner:         github.User{Login: owner},
+		Repo:          repo,
+		DefaultBranch: c.defaultBranch(owner, repo),
+	})
+}
+
+// contents serves github.GetFile and github.GetFileInfo.
+func (c *Client) contents(owner, repo, ref, p string) (*http.Response, error) {
+	folder, ok := c.folder(owner, repo, ref)
+	if !ok {
+		return response(http.StatusNotFound, ""), nil
+	}
+
+	full, err := c.path(owner, repo, folder, p)
+	if err != nil {
+		return response(http.StatusBadRequest, ""), err
+	}
+
+	info, err := os.Stat(full)
+	if err != nil {
+		return response(http.StatusNotFound, ""), nil
+	}
+
+	if !info.IsDir() {
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return response(http.StatusInternalServerError, ""), fmt.Errorf("failed to read %s: %w", full, err)
+		}
+
+		return jsonResponse(http.StatusOK, file(p, full, data))
+	}
+
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		return response(http.StatusInternalServerError, ""), fmt.Errorf("failed to read %s: %w", full, err)
+	}
+
+	out := []content{}
+
+	for _, e := range entries {
+		entryPath := path.Join(p, e.Name())
+
+		if e.IsDir() {
+			out = append(out, content{Type: "dir", Name: e.Name(), Path: entryPath})
+
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(full, e.Name()))
+		if err != nil {
+			return response(http.StatusInternalServerError, ""), fmt.Errorf("failed to read %s: %w", full, err)
+		}
+
+		// NOTE: The API doesn't list the content in directories.
+		f := file(entryPath, filepath.Join(full, e.Name()), data)
+		f.Content = ""
+		f.Encoding = ""
+
+		out = append(out, f)
+	}
+
+	return jsonResponse(http.StatusOK, out)
+}
+
+// branch serves github.GetBranch.
+func (c *Client) branch(owner, repo, name string) (*http.Response, error) {
+	folder, ok := c.folder(owner, repo, name)
+	if !ok {
+		return response(http.StatusNotFound, ""), nil
+	}
+
+	return jsonResponse(http.StatusOK, github.Branch{
+		Name:   name,
+		Commit: github.Commit{SHA: commitSHA(owner, repo, folder)},
+	})
+}
+
+// tree serves github.GetTree.
+func (c *Client) tree(owner, repo, ref string) (*http.Response, error) {
+	folder, ok := c.folder(owner, repo, ref)
+	if !ok {
+		return response(http.StatusNotFound, ""), nil
+	}
+
+	root, err := c.path(owner, repo, folder)
+	if err != nil {
+		return response(http.StatusBadRequest, ""), err
+	}
+
+	t := github.Tree{SHA: commitSHA(owner, repo, folder)}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == root {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped below.
+		}
+
+		e := github.TreeEntry{Path: filepath.ToSlash(rel), Type: github.TypeTree, Mode: "040000"}
+
+		if !d.IsDir() {
+			content, err := os.ReadFile(p)
+			if err != nil {
+				return err //nolint:wrapcheck // Wrapped below.
+			}
+
+			e.Type = github.TypeBlob
+			e.Mode = "100644"
+			e.SHA = github.BlobSHA(string(content))
+			e.Size = len(content)
+		}
+
+		t.Entries = append(t.Entries, e)
+
+		return nil
+	})
+	if err != nil {
+		return response(http.StatusInternalServerError, ""), fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	return jsonResponse(http.StatusOK, t)
+}
+
+// tags serves github.GetTags, listing all the folders of the repository.
+func (c *Client) tags(owner, repo, page string) (*http.Response, error) {
+	refs, err := c.refs(owner, repo)
+	if err != nil {
+		return response(http.StatusNotFound, ""), nil //nolint:nilerr // Missing repositories are 404.
+	}
+
+	tags := []github.Tag{}
+
+	// NOTE: All the tags are on the first page.
+	if n, _ := strconv.Atoi(page); n <= 1 {
+		for _, r := range refs {
+			tags = append(tags, github.Tag{Name: r, Commit: github.Commit{SHA: commitSHA(owner, repo, r)}})
+		}
+	}
+
+	return jsonResponse(http.StatusOK, tags)
+}
+
+// folder returns the folder a ref, or a commit hash, points to. An empty ref
+// points to the default branch.
+func (c *Client) folder(owner, repo, ref string) (string, bool) {
+	if ref == "" || ref == headRef {
+		ref = c.defaultBranch(owner, repo)
+	}
+
+	c.mu.Lock()
+	folder, ok := c.branches[owner+"/"+repo+"@"+ref]
+	c.mu.Unlock()
+
+	if !ok {
+		folder = ref
+	}
+
+	if commit, ok := c.commitFolder(owner, repo, ref); ok {
+		folder = commit
+	}
+
+	dir, err := c.path(owner, repo, folder)
+	if err != nil {
+		return "", false
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	return folder, true
+}
+
+func (c *Client) defaultBranch(owner, repo string) string {
+	head, err := c.path(owner, repo, headFile)
+	if err != nil {
+		return defaultBranch
+	}
+
+	content, err := os.ReadFile(head)
+	if err != nil {
+		return defaultBranch
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// refs lists the folders of the repository.
+func (c *Client) refs(owner, repo string) ([]string, error) {
+	dir, err := c.path(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	refs := []string{}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			refs = append(refs, e.Name())
+		}
+	}
+
+	return refs, nil
+}
+
+// path joins the elements under the root, none of them can leave it.
+func (c *Client) path(elem ...string) (string, error) {
+	for _, e := range elem {
+		if e != "" && !filepath.IsLocal(e) {
+			return "", fmt.Errorf("%w: %s", errOutsideRoot, e)
+		}
+	}
+
+	return filepath.Join(append([]string{c.root}, elem...)...), nil
+}
+
+// content is an entry of the contents API.
+// NOTE: github.File can't be used, as its config fields would be overwritten.
+type content struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	SHA      string `json:"sha,omitempty"`
+	Size     int    `json:"size"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	HTMLURL  string `json:"html_url,omitempty"`
+}
+
+func file(p, full string, data []byte) content {
+	return content{
+		Type:     "file",
+		Name:     path.Base(p),
+		Path:     p,
+		Content:  base64.StdEncoding.EncodeToString(data),
+		Encoding: "base64",
+		SHA:      github.BlobSHA(string(data)),
+		Size:     len(data),
+		HTMLURL:  "file://" + full,
+	}
+}
+
+// commitSHA returns a fake commit hash, stable for each folder.
+func commitSHA(owner, repo, folder string) string {
+	h := sha1.New() //nolint:gosec // Only used to fake commit hashes.
+	fmt.Fprintf(h, "%s/%s@%s", owner, repo, folder)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func jsonResponse(status int, v any) (*http.Response, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return response(http.StatusInternalServerError, ""), fmt.Errorf("failed to marshal the response: %w", err)
+	}
+
+	return response(status, string(body)), nil
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     strconv.Itoa(status) + " " + http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
@@ -0,0 +1,315 @@
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/backend/gitlab"
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+func setup(t *testing.T) (*Client, *github.GitHub) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	files := map[string]string{
+		"owner/repo/HEAD":            "trunk\n",
+		"owner/repo/trunk/a":         "content a",
+		"owner/repo/trunk/dir/b":     "content b",
+		"owner/repo/v1.0.0/a":        "old a",
+		"owner/other/main/README.md": "readme",
+		"secret":                     "secret",
+	}
+
+	for p, content := range files {
+		full := filepath.Join(root, p)
+
+		if err := os.MkdirAll(filepath.Dir(full), 0o700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := New(root, filepath.Join(root, "journal.jsonl"))
+
+	return c, github.New(c, "https://api.github.com")
+}
+
+func repo(name string) github.Repo {
+	return github.Repo{Owner: github.User{Login: "owner"}, Repo: name}
+}
+
+func TestGetRepo(t *testing.T) {
+	t.Parallel()
+
+	_, g := setup(t)
+
+	t.Run("reads the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		for name, want := range map[string]string{"repo": "trunk", "other": "main"} {
+			got, err := g.GetDefaultBranchName(t.Context(), repo(name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != want {
+				t.Fatalf("want %q but got %q", want, got)
+			}
+		}
+	})
+
+	t.Run("fails on a missing repo", func(t *testing.T) {
+		t.Parallel()
+
+		r := repo("missing")
+		if err := g.GetRepo(t.Context(), &r); err == nil {
+			t.Fatal("want error but got nil")
+		}
+	})
+}
+
+func TestGetFile(t *testing.T) {
+	t.Parallel()
+
+	_, g := setup(t)
+
+	t.Run("reads a file at a ref", func(t *testing.T) {
+		t.Parallel()
+
+		for ref, want := range map[string]string{"": "content a", "trunk": "content a", "v1.0.0": "old a"} {
+			f := github.File{Repo: repo("repo"), Path: "a", Ref: ref}
+			if err := g.GetFile(t.Context(), &f); err != nil {
+				t.Fatal(err)
+			}
+
+			if f.Content != want {
+				t.Fatalf("want %q but got %q", want, f.Content)
+			}
+
+			if f.SHA != github.BlobSHA(want) {
+				t.Fatalf("want SHA %q but got %q", github.BlobSHA(want), f.SHA)
+			}
+
+			if f.Ref != ref || !f.Repo.Equal(repo("repo")) {
+				t.Fatalf("want the config fields kept but got %#v", f)
+			}
+		}
+	})
+
+	t.Run("reads a file info", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo("repo"), Path: "dir/b", Ref: "trunk"}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.SHA != github.BlobSHA("content b") || f.Content != "" {
+			t.Fatalf("want the info only but got %#v", f)
+		}
+	})
+
+	t.Run("fails on missing files", func(t *testing.T) {
+		t.Parallel()
+
+		for _, f := range []github.File{
+			{Repo: repo("repo"), Path: "missing", Ref: "trunk"},
+			{Repo: repo("repo"), Path: "a", Ref: "missing"},
+			{Repo: repo("missing"), Path: "a"},
+		} {
+			if err := g.GetFile(t.Context(), &f); !errors.Is(err, github.ErrMissingFile) {
+				t.Fatalf("want %v but got %v", github.ErrMissingFile, err)
+			}
+		}
+	})
+
+	t.Run("fails outside of the root", func(t *testing.T) {
+		t.Parallel()
+
+		f := github.File{Repo: repo("repo"), Path: "../../../secret", Ref: "trunk"}
+		if err := g.GetFile(t.Context(), &f); err == nil {
+			t.Fatal("want error but got nil")
+		}
+	})
+}
+
+func TestGetTree(t *testing.T) {
+	t.Parallel()
+
+	_, g := setup(t)
+
+	tree, err := g.GetTree(t.Context(), repo("repo"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a", "dir/b"}
+	if got := tree.Blobs(); !slices.Equal(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	t.Parallel()
+
+	_, g := setup(t)
+
+	tags, err := g.GetTags(t.Context(), repo("repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, tag := range tags {
+		got = append(got, tag.Name)
+	}
+
+	want := []string{"trunk", "v1.0.0"}
+	if !slices.Equal(want, got) {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+}
+
+func TestBranches(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates a branch on the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		c, g := setup(t)
+
+		base, head, err := g.GetBaseAndHeadBranches(t.Context(), repo("repo"), "new")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !head.New {
+			t.Fatal("want a new head branch")
+		}
+
+		f := github.File{Repo: repo("repo"), Path: "a", Ref: head.Name}
+		if err := g.GetFile(t.Context(), &f); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := g.GetBranch(t.Context(), repo("repo"), "new")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Commit.SHA != base.Commit.SHA {
+			t.Fatalf("want %q but got %q", base.Commit.SHA, got.Commit.SHA)
+		}
+
+		if err := g.DeleteBranch(t.Context(), repo("repo"), "new"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.GetBranch(t.Context(), repo("repo"), "new"); !errors.Is(err, github.ErrNoBranch) {
+			t.Fatalf("want %v but got %v", github.ErrNoBranch, err)
+		}
+
+		if len(c.Journal()) != 2 {
+			t.Fatalf("want 2 entries but got %#v", c.Journal())
+		}
+	})
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	c, g := setup(t)
+	ctx := t.Context()
+
+	if _, err := g.CreateBlob(ctx, repo("repo"), "content"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.UpdateBranch(ctx, repo("repo"), "trunk", "sha"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{Method: "POST", Path: "/repos/owner/repo/git/blobs", Body: json.RawMessage(
+			`{"content":"Y29udGVudA==","encoding":"base64"}`,
+		)},
+		{Method: "PATCH", Path: "/repos/owner/repo/git/refs/heads/trunk", Body: json.RawMessage(
+			`{"sha":"sha"}`,
+		)},
+	}
+
+	got := c.Journal()
+	if len(got) != len(want) {
+		t.Fatalf("want %d entries but got %#v", len(want), got)
+	}
+
+	for i := range want {
+		if got[i].Method != want[i].Method || got[i].Path != want[i].Path || string(got[i].Body) != string(want[i].Body) {
+			t.Fatalf("want %s but got %s", want[i].Body, got[i].Body)
+		}
+	}
+
+	t.Run("appends to the file", func(t *testing.T) {
+		t.Parallel()
+
+		content, err := os.ReadFile(c.journal.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lines := strings.Count(string(content), "\n"); lines != len(want) {
+			t.Fatalf("want %d lines but got %q", len(want), content)
+		}
+	})
+}
+
+func TestUnsupported(t *testing.T) {
+	t.Parallel()
+
+	c, _ := setup(t)
+
+	t.Run("rejects the head requests", func(t *testing.T) {
+		t.Parallel()
+
+		u := "https://api.github.com/repos/owner/repo/contents/a"
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodHead, u, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Do(req); !errors.Is(err, errors.ErrUnsupported) {
+			t.Fatalf("want %v but got %v", errors.ErrUnsupported, err)
+		}
+	})
+
+	t.Run("rejects GitLab", func(t *testing.T) {
+		t.Parallel()
+
+		gl := gitlab.New(c, gitlab.DefaultEndpoint)
+		f := github.File{Repo: github.Repo{Owner: github.User{Login: "owner"}, Repo: "repo", Forge: github.ForgeGitLab}}
+
+		if err := gl.GetFileInfo(t.Context(), &f); !errors.Is(err, errGitLab) {
+			t.Fatalf("want %v but got %v", errGitLab, err)
+		}
+
+		if _, err := gl.CreateBranch(t.Context(), f.Repo, "head", "sha"); !errors.Is(err, errGitLab) {
+			t.Fatalf("want %v but got %v", errGitLab, err)
+		}
+
+		if len(c.Journal()) != 0 {
+			t.Fatalf("want no write but got %v", c.Journal())
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+// binarySniffLen is how much of the content is checked, like git does.
+const binarySniffLen = 8000
+
+var errBinary = errors.New("binary content is not supported")
+
+// isBinary reports whether the content looks binary, i.e. it contains a NUL
+// byte early on.
+func isBinary(s string) bool {
+	return strings.IndexByte(s[:min(len(s), binarySniffLen)], 0) >= 0
+}
+
+// verbatim reports whether `from` is written as-is, so that its blob SHA can
+// be compared instead of its content.
+func (l *Link) verbatim() bool {
+	return !l.ShouldRender() &&
+		len(l.Transforms) == 0 &&
+		l.Section == nil &&
+		l.Merge == nil &&
+		len(l.Sources) == 0
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestIsBinary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "empty"},
+		{name: "text", content: "hello\nworld\n"},
+		{name: "utf-8", content: "héllo ✓"},
+		{name: "nul byte", content: "\x89PNG\x00", want: true},
+		{name: "late nul byte", content: strings.Repeat("a", binarySniffLen) + "\x00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isBinary(test.content); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinkBinary(t *testing.T) {
+	t.Parallel()
+
+	binary := "\x89PNG\x00{{ .Missing }}"
+	yes := true
+
+	t.Run("skips rendering and transforming", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:       github.File{Content: binary},
+			Template:   &yes,
+			Transforms: []Transform{{Prepend: "a"}},
+		}
+
+		if err := l.render(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if err := l.transform(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.From.Content != binary {
+			t.Fatalf("want the content unchanged, got %q", l.From.Content)
+		}
+	})
+
+	t.Run("fails with a section", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:    github.File{Content: binary},
+			Section: &Section{},
+		}
+
+		if _, err := l.NeedUpdate(t.Context(), gmock.Getter{}, github.Branch{}); !errors.Is(err, errBinary) {
+			t.Fatalf("want error %v, got %v", errBinary, err)
+		}
+	})
+}
+
+func TestLinkSameBlob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("compares the blobs", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				// NOTE: The content is not compared, only the SHA.
+				f.SHA = "sha"
+
+				return nil
+			},
+		}
+
+		l := &Link{
+			From: github.File{SHA: "sha", Content: "large"},
+			To:   github.File{SHA: "other"},
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, github.Branch{Name: "head"})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatal("want no update")
+		}
+	})
+
+	t.Run("computes the blob of modified content", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:       github.File{SHA: "sha", Content: "ab"},
+			Transforms: []Transform{{Prepend: "a"}},
+		}
+
+		got, err := l.wantSHA(t.Context(), gmock.Getter{}, &github.File{SHA: "sha"})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if want := github.BlobSHA("ab"); got != want {
+			t.Fatalf("want %q, got %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/format"
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const defaultCommitMessage = `auto(ln): update links
+{{ range .Data }}
+- {{ .To.Path }}: {{ if .Orphan }}deleted, {{ .From }} was removed{{ else }}{{ .From.HTMLURL }}{{ end }}
+{{- end }}
+{{- with .Data.Changes }}
+
+Upstream changes:
+{{ range . }}
+- {{ .ShortSHA }} {{ .Title }}
+{{- end }}
+{{- end }}
+`
+
+// Commit configures the commit written for a group of links.
+type Commit struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+// fillDefaults sets the missing fields from d.
+func (c *Commit) fillDefaults(d Commit) {
+	if c.Message == "" {
+		c.Message = d.Message
+	}
+}
+
+func (c *Commit) validate() error {
+	return validateTextTemplate("commit.message", c.Message)
+}
+
+// commit writes all the links in a single commit on the head branch.
+// The branch is only moved if every link succeeded.
+//
+// It uses the Git Data API:
+// https://docs.github.com/en/rest/guides/using-the-rest-api-to-interact-with-your-git-database?apiVersion=2022-11-28
+func (l *Links) commit(
+	ctx context.Context,
+	g github.GetterUpdater,
+	f format.Formatter,
+	head github.Branch,
+) error {
+	repo := (*l)[0].To.Repo
+
+	headTree, err := g.GetTree(ctx, repo, head.Commit.SHA)
+	if err != nil {
+		return fmt.Errorf("failed to get head tree: %w", err)
+	}
+
+	modes := map[string]string{}
+	for _, e := range headTree.Entries {
+		modes[e.Path] = e.Mode
+	}
+
+	entries := []github.TreeEntry{}
+
+	for _, link := range *l {
+		entry, err := link.treeEntry(ctx, g, modes[link.To.Path])
+		if err != nil {
+			return fmt.Errorf("failed to prepare %s: %w", link, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	tree, err := g.CreateTree(ctx, repo, headTree.SHA, entries)
+	if err != nil {
+		return fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	msg, err := f.FormatText((*l)[0].Commit.Message, *l)
+	if err != nil {
+		return fmt.Errorf("failed to format the commit message: %w", err)
+	}
+
+	// NOTE: The trailers are always added, so the next sync can list the
+	// upstream changes, see Link.history.
+	if trailers := l.trailers(); trailers != "" {
+		msg = strings.TrimRight(msg, "\n") + "\n\n" + trailers
+	}
+
+	commit, err := g.CreateCommit(ctx, repo, msg, tree.SHA, []string{head.Commit.SHA})
+	if err != nil {
+		return fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	if _, err := g.UpdateBranch(ctx, repo, head.Name, commit.SHA); err != nil {
+		return fmt.Errorf("failed to move the head branch: %w", err)
+	}
+
+	log.Info("Committed links", "repo", repo, "branch", head.Name, "commit", commit.SHA)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+	"github.com/nobe4/action-ln/internal/template"
+)
+
+var errConcat = errors.New("failed to concatenate")
+
+// Concat joins all the `from` files, in order, into a single `to`.
+type Concat struct {
+	// Separator is inserted between the files.
+	Separator string `json:"separator,omitempty" yaml:"separator,omitempty"`
+
+	// Header is a template inserted before each file.
+	// It gets the same data as the templated content, and `.From`.
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+}
+
+func (c *Concat) validate() error {
+	if err := template.Validate(c.Header); err != nil {
+		return fmt.Errorf("%w %q: %w", errInvalidTemplate, "concat.header", err)
+	}
+
+	return nil
+}
+
+// Concat merges the concat links sharing the same `to` into a single link,
+// whose sources are all their `from` files.
+func (l *Links) Concat() {
+	newL := Links{}
+	byTo := map[string]*Link{}
+
+	for _, link := range *l {
+		if link.Concat == nil {
+			newL = append(newL, link)
+
+			continue
+		}
+
+		key := link.To.String()
+
+		if c, ok := byTo[key]; ok {
+			c.Sources = append(c.Sources, link.From)
+
+			continue
+		}
+
+		link.Sources = []github.File{link.From}
+		byTo[key] = link
+		newL = append(newL, link)
+	}
+
+	*l = newL
+}
+
+// populateSources gets all the sources and joins them in `from`.
+func (l *Link) populateSources(ctx context.Context, g github.Getter, c *Config) error {
+	parts := make([]string, 0, len(l.Sources))
+
+	for i := range l.Sources {
+		l.From = l.Sources[i]
+
+		if err := l.populateFrom(ctx, g); err != nil {
+			return err
+		}
+
+		l.Sources[i] = l.From
+
+		part, err := l.Concat.part(l.From, l.templateData(c))
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", errConcat, l.From, err)
+		}
+
+		parts = append(parts, part)
+	}
+
+	l.From = l.Sources[0]
+	l.From.Content = strings.Join(parts, l.Concat.Separator)
+
+	log.Debug("Concatenated sources", "link", l, "sources", l.Sources)
+
+	return nil
+}
+
+// part returns the header and content of f, ending with a newline.
+func (c *Concat) part(f github.File, data templateData) (string, error) {
+	content := f.Content
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if c.Header == "" {
+		return content, nil
+	}
+
+	header := c.Header
+	if err := template.Update(&header, struct {
+		templateData
+		From github.File
+	}{templateData: data, From: f}); err != nil {
+		return "", err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	return header + content, nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestLinksConcat(t *testing.T) {
+	t.Parallel()
+
+	concat := &Concat{}
+
+	l := Links{
+		{From: github.File{Path: "a"}, To: github.File{Path: "x"}, Concat: concat},
+		{From: github.File{Path: "b"}, To: github.File{Path: "y"}},
+		{From: github.File{Path: "c"}, To: github.File{Path: "x"}, Concat: concat},
+		{From: github.File{Path: "d"}, To: github.File{Path: "z"}, Concat: concat},
+	}
+
+	l.Concat()
+
+	want := []string{
+		"/:a@ + /:c@ -> /:x@",
+		"/:b@ -> /:y@",
+		"/:d@ -> /:z@",
+	}
+
+	if len(l) != len(want) {
+		t.Fatalf("want %d links, got %v", len(want), l)
+	}
+
+	for i, w := range want {
+		if l[i].String() != w {
+			t.Fatalf("want link %d to be %q, got %q", i, w, l[i].String())
+		}
+	}
+}
+
+func TestLinkPopulateSources(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		FileHandler: func(f *github.File) error {
+			switch f.Path {
+			case "a":
+				f.Content = "a"
+			case "b":
+				f.Content = "b\n"
+			default:
+				return errTest
+			}
+
+			return nil
+		},
+	}
+
+	t.Run("joins the sources", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			Sources: []github.File{{Path: "a", Ref: "main"}, {Path: "b", Ref: "main"}},
+			Concat:  &Concat{Separator: "\n", Header: "# {{ .From.Path }} -> {{ .Link.To.Path }}\n"},
+			To:      github.File{Path: "to"},
+		}
+
+		if err := l.populateSources(t.Context(), g, &Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		want := "# a -> to\na\n\n# b -> to\nb\n"
+		if l.From.Content != want {
+			t.Fatalf("want %q, got %q", want, l.From.Content)
+		}
+
+		if l.From.Path != "a" || l.Sources[1].Content != "b\n" {
+			t.Fatalf("want the sources to be populated, got %+v", l.Sources)
+		}
+	})
+
+	t.Run("fails to get a source", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			Sources: []github.File{{Path: "a", Ref: "main"}, {Path: "c", Ref: "main"}},
+			Concat:  &Concat{},
+		}
+
+		if err := l.populateSources(t.Context(), g, &Config{}); !errors.Is(err, errMissingFrom) {
+			t.Fatalf("want error %v, got %v", errMissingFrom, err)
+		}
+	})
+
+	t.Run("fails to render the header", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			Sources: []github.File{{Path: "a", Ref: "main"}},
+			Concat:  &Concat{Header: "{{ .Missing }}"},
+		}
+
+		if err := l.populateSources(t.Context(), g, &Config{}); !errors.Is(err, errConcat) {
+			t.Fatalf("want error %v, got %v", errConcat, err)
+		}
+	})
+}
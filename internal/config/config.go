@@ -34,6 +34,12 @@ type Config struct {
 	Source   github.File `json:"source"   yaml:"source"`
 	Defaults Defaults    `json:"defaults" yaml:"defaults"`
 	Links    Links       `json:"links"    yaml:"links"`
+
+	// refs caches the resolved refs, see Links.ResolveRefs.
+	refs map[string]string
+
+	// synced caches the synced commits of the orphans, see Config.syncedSHA.
+	synced map[string]string
 }
 
 func New(source github.File, repo github.Repo) *Config {
@@ -44,11 +50,16 @@ func New(source github.File, repo github.Repo) *Config {
 				From: github.File{Repo: repo},
 				To:   github.File{Repo: repo},
 			},
+			Branch: defaultBranch,
+			Pull:   Pull{Title: defaultPullTitle, Body: defaultPullBody},
+			Commit: Commit{Message: defaultCommitMessage},
 		},
 	}
 }
 
-func (c *Config) Parse(r io.Reader) error {
+// Parse reads the config from r.
+// The Getter is used to expand the glob patterns found in the links.
+func (c *Config) Parse(ctx context.Context, r io.Reader, g github.Getter) error {
 	var err error
 
 	rawC := RawConfig{}
@@ -59,11 +70,11 @@ func (c *Config) Parse(r io.Reader) error {
 		return fmt.Errorf("%w: %w", errInvalidYAML, err)
 	}
 
-	if err := c.parseDefaults(rawC.Defaults); err != nil {
+	if err := c.parseDefaults(ctx, g, rawC.Defaults); err != nil {
 		return fmt.Errorf("%w: %w", errInvalidDefaults, err)
 	}
 
-	if c.Links, err = c.parseLinks(rawC.Links); err != nil {
+	if c.Links, err = c.parseLinks(ctx, g, rawC.Links); err != nil {
 		return fmt.Errorf("%w: %w", errInvalidLinks, err)
 	}
 
@@ -74,11 +85,27 @@ func (c *Config) Populate(ctx context.Context, g github.Getter) error {
 	log.Group("Populate config")
 	defer log.GroupEnd()
 
+	// NOTE: Directories are expanded here rather than when parsing, so that
+	// they always reflect the current content of the `from` directory.
+	if err := c.Links.ExpandDirectories(ctx, g, c); err != nil {
+		return fmt.Errorf("failed to expand directories: %w", err)
+	}
+
 	for i, l := range c.Links {
-		if err := l.populate(ctx, g); err != nil {
+		if err := l.populate(ctx, g, c); err != nil {
 			return fmt.Errorf("failed to populate link %#v: %w", l, err)
 		}
 
+		// NOTE: Rendering and transforming here means NeedUpdate compares the
+		// final content.
+		if err := l.render(c); err != nil {
+			return err
+		}
+
+		if err := l.transform(c); err != nil {
+			return err
+		}
+
 		// TODO: check if this is really necessary.
 		c.Links[i] = l
 	}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
 )
 
 //go:embed fixtures/*
@@ -31,9 +32,46 @@ func TestConfigParseAll(t *testing.T) {
 				Repo: repo,
 			}
 
+			// Used to expand the globs, see all-cases.yaml.
+			g := gmock.Getter{
+				TreeHandler: func(r github.Repo, _ string) (github.Tree, error) {
+					// z.txt was synced, then removed from `from`, see `delete`.
+					if r.Repo == "to_repo" {
+						return github.Tree{
+							Entries: []github.TreeEntry{
+								{Path: "a.txt", Type: github.TypeBlob},
+								{Path: "z.txt", Type: github.TypeBlob},
+							},
+						}, nil
+					}
+
+					return github.Tree{
+						Entries: []github.TreeEntry{
+							{Path: "a.txt", Type: github.TypeBlob},
+							{Path: "b.txt", Type: github.TypeBlob},
+							{Path: "c.md", Type: github.TypeBlob},
+							{Path: "d", Type: github.TypeTree},
+							{Path: "d/e.txt", Type: github.TypeBlob},
+							{Path: "d/f", Type: github.TypeTree},
+							{Path: "d/f/g.txt", Type: github.TypeBlob},
+						},
+					}, nil
+				},
+				CommitsHandler: func(r github.Repo, _, path string, _ int) ([]github.RepoCommit, error) {
+					if r.Repo != "to_repo" || path != "z.txt" {
+						return []github.RepoCommit{}, nil
+					}
+
+					c := github.RepoCommit{SHA: "sync"}
+					c.Commit.Message = "sync\n\nSynced-From: from_owner/from_repo:z.txt@sha"
+
+					return []github.RepoCommit{c}, nil
+				},
+			}
+
 			c := New(source, repo)
 
-			err := c.Parse(strings.NewReader(content))
+			err := c.Parse(t.Context(), strings.NewReader(content), g)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -1,31 +1,94 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
 	"github.com/nobe4/action-ln/internal/log"
 )
 
+var errDefaultsLinkOption = errors.New("must be set at the top level of defaults, not under defaults.link")
+
 type RawDefaults struct {
-	Link RawLink `yaml:"link"`
+	Link   RawLink `yaml:"link"`
+	Branch string  `yaml:"branch"`
+	Rebase *bool   `yaml:"rebase"`
+	Pull   Pull    `yaml:"pull"`
+	Commit Commit  `yaml:"commit"`
 }
 
 type Defaults struct {
 	Link *Link `json:"link" yaml:"link"`
+
+	// Branch is the head branch the links are written to.
+	Branch string `json:"branch" yaml:"branch"`
+
+	// Rebase resets the head branches onto base when they are behind.
+	Rebase *bool `json:"rebase,omitempty" yaml:"rebase,omitempty"`
+
+	// Pull configures the pull requests.
+	Pull Pull `json:"pull" yaml:"pull"`
+
+	// Commit configures the commits.
+	Commit Commit `json:"commit" yaml:"commit"`
 }
 
 func (d *Defaults) Equal(o *Defaults) bool {
 	return d.Link.Equal(o.Link)
 }
 
-func (c *Config) parseDefaults(raw RawDefaults) error {
+func (d *Defaults) validate() error {
+	if err := validateTextTemplate("branch", d.Branch); err != nil {
+		return err
+	}
+
+	if err := d.Pull.validate(); err != nil {
+		return err
+	}
+
+	return d.Commit.validate()
+}
+
+func (c *Config) parseDefaults(ctx context.Context, g github.Getter, raw RawDefaults) error {
 	log.Debug("Parse defaults", "raw", raw)
 
-	links, err := c.parseLink(raw.Link)
+	if o := raw.Link.groupOption(); o != "" {
+		return fmt.Errorf("%q %w", o, errDefaultsLinkOption)
+	}
+
+	if raw.Branch != "" {
+		c.Defaults.Branch = raw.Branch
+	}
+
+	if raw.Rebase != nil {
+		c.Defaults.Rebase = raw.Rebase
+	}
+
+	raw.Pull.fillDefaults(c.Defaults.Pull)
+	c.Defaults.Pull = raw.Pull
+
+	raw.Commit.fillDefaults(c.Defaults.Commit)
+	c.Defaults.Commit = raw.Commit
+
+	if err := c.Defaults.validate(); err != nil {
+		return err
+	}
+
+	links, err := c.parseLink(ctx, g, raw.Link)
 	if err != nil {
 		return err
 	}
 
 	switch len(links) {
 	case 0:
+		// NOTE: A link without `from` is dropped, but its options still apply.
+		raw.Link.applyOptions(c.Defaults.Link)
+
+		if err := c.Defaults.Link.validate(); err != nil {
+			return err
+		}
 	case 1:
 		c.Defaults.Link = links[0]
 	default:
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
 )
 
 func TestParseDefault(t *testing.T) {
@@ -17,7 +19,7 @@ func TestParseDefault(t *testing.T) {
 		c := New(github.File{}, repo)
 		raw := RawDefaults{}
 
-		if err := c.parseDefaults(raw); err != nil {
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, raw); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
@@ -48,7 +50,7 @@ func TestParseDefault(t *testing.T) {
 			},
 		}
 
-		if err := c.parseDefaults(raw); err != nil {
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, raw); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
@@ -82,7 +84,7 @@ func TestParseDefault(t *testing.T) {
 			},
 		}
 
-		if err := c.parseDefaults(raw); err != nil {
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, raw); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
@@ -90,4 +92,87 @@ func TestParseDefault(t *testing.T) {
 			t.Fatalf("expected %v, got %v", want, c.Defaults.Link)
 		}
 	})
+
+	t.Run("parses the branch and pull", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+		raw := RawDefaults{
+			Branch: "branch",
+			Pull:   Pull{Title: "title", Body: "body"},
+			Commit: Commit{Message: "message"},
+		}
+
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, raw); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if c.Defaults.Branch != "branch" {
+			t.Fatalf("expected branch %q, got %q", "branch", c.Defaults.Branch)
+		}
+
+		if p := c.Defaults.Pull; p.Title != "title" || p.Body != "body" {
+			t.Fatalf("expected pull title and body, got %+v", p)
+		}
+
+		if c.Defaults.Commit.Message != "message" {
+			t.Fatalf("expected commit message %q, got %q", "message", c.Defaults.Commit.Message)
+		}
+	})
+
+	t.Run("keeps the default branch and pull", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, RawDefaults{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if c.Defaults.Branch != defaultBranch {
+			t.Fatalf("expected branch %q, got %q", defaultBranch, c.Defaults.Branch)
+		}
+
+		if p := c.Defaults.Pull; p.Title != defaultPullTitle || p.Body != defaultPullBody {
+			t.Fatalf("expected default pull title and body, got %+v", p)
+		}
+
+		if c.Defaults.Commit.Message != defaultCommitMessage {
+			t.Fatalf("expected commit message %q, got %q", defaultCommitMessage, c.Defaults.Commit.Message)
+		}
+	})
+
+	t.Run("fails on a group option under link", func(t *testing.T) {
+		t.Parallel()
+
+		draft := false
+
+		for _, l := range []RawLink{
+			{Branch: "b"},
+			{Rebase: &draft},
+			{Pull: Pull{Title: "t"}},
+			{Pull: Pull{Draft: &draft}},
+			{Commit: Commit{Message: "m"}},
+		} {
+			c := New(github.File{}, github.Repo{})
+
+			err := c.parseDefaults(t.Context(), gmock.Getter{}, RawDefaults{Link: l})
+			if !errors.Is(err, errDefaultsLinkOption) {
+				t.Fatalf("expected error %v for %+v, got %v", errDefaultsLinkOption, l, err)
+			}
+		}
+	})
+
+	t.Run("fails on an invalid template", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+		raw := RawDefaults{
+			Pull: Pull{Body: "{{ .Data "},
+		}
+
+		if err := c.parseDefaults(t.Context(), gmock.Getter{}, raw); !errors.Is(err, errInvalidTemplate) {
+			t.Fatalf("expected error %v, got %v", errInvalidTemplate, err)
+		}
+	})
 }
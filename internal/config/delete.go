@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+var errFindOrphans = errors.New("failed to find deleted files")
+
+// orphans returns a link for each file in the `to` repository that matches
+// but is not part of `kept`, and that a previous sync wrote.
+// `match` maps a `to` path to the `from` path it would be linked from.
+func (l *Link) orphans(
+	ctx context.Context,
+	g github.Getter,
+	c *Config,
+	kept Links,
+	match func(string) (string, bool),
+) (Links, error) {
+	if !l.ShouldDelete() {
+		return Links{}, nil
+	}
+
+	tree, err := g.GetTree(ctx, l.To.Repo, l.To.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", errFindOrphans, l.To, err)
+	}
+
+	keep := map[string]bool{}
+	sources := map[string]bool{}
+
+	for _, k := range kept {
+		keep[k.To.Path] = true
+		sources[k.From.Path] = true
+	}
+
+	// NOTE: The files that still have a source are dropped first, as checking
+	// the trailers of the others costs a request each.
+	candidates := Links{}
+
+	for _, p := range tree.Blobs() {
+		if keep[p] {
+			continue
+		}
+
+		from, ok := match(p)
+		if !ok || sources[from] {
+			continue
+		}
+
+		nl := *l
+		nl.From.Path = from
+		nl.To.Path = p
+		nl.Orphan = true
+
+		candidates = append(candidates, &nl)
+	}
+
+	links := Links{}
+
+	for _, nl := range candidates {
+		// NOTE: Files that only ever existed in `to` are kept, only the ones
+		// with a `Synced-From` trailer for their `from` are deleted.
+		synced, err := c.syncedSHA(ctx, g, nl)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", errFindOrphans, nl.To, err)
+		}
+
+		if synced == "" {
+			log.Debug("Keeping a file that was never synced", "to", nl.To)
+
+			continue
+		}
+
+		links = append(links, nl)
+	}
+
+	log.Debug("Found deleted files", "link", l, "links", links)
+
+	return links, nil
+}
+
+func (l *Link) needDelete(ctx context.Context, g github.Getter, head github.Branch) (bool, error) {
+	if l.To.SHA == "" {
+		log.Debug("File is already deleted", "to", l.To)
+
+		return false, nil
+	}
+
+	headTo := &github.File{
+		Repo: l.To.Repo,
+		Path: l.To.Path,
+		Ref:  head.Name,
+	}
+
+	if err := g.GetFileInfo(ctx, headTo); err != nil {
+		if errors.Is(err, github.ErrMissingFile) {
+			log.Debug("File is already deleted", "to@head", headTo)
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get to@head %s: %w", headTo, err)
+	}
+
+	l.To.SHA = headTo.SHA
+
+	return true, nil
+}
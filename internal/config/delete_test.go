@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestOrphans(t *testing.T) {
+	t.Parallel()
+
+	yes := true
+
+	tree := github.Tree{
+		Entries: []github.TreeEntry{
+			{Path: "a.txt", Type: github.TypeBlob},
+			{Path: "b.txt", Type: github.TypeBlob},
+			{Path: "c.md", Type: github.TypeBlob},
+		},
+	}
+
+	match := func(p string) (string, bool) { return "from/" + p, matchGlob("*.txt", p) }
+
+	// NOTE: Only b.txt was written by a previous sync.
+	commits := func(_ github.Repo, _, path string, _ int) ([]github.RepoCommit, error) {
+		if path != "b.txt" {
+			return []github.RepoCommit{}, nil
+		}
+
+		c := github.RepoCommit{SHA: "to"}
+		c.Commit.Message = "sync\n\nSynced-From: /:from/b.txt@sha\n"
+
+		return []github.RepoCommit{c}, nil
+	}
+
+	t.Run("does nothing without delete", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				t.Fatal("TreeHandler should not be called in this test")
+
+				return github.Tree{}, nil
+			},
+		}
+
+		l := &Link{}
+
+		got, err := l.orphans(t.Context(), g, &Config{}, Links{}, match)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 0 {
+			t.Fatalf("expected no orphan, got %v", got)
+		}
+	})
+
+	t.Run("fails to get the tree", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return github.Tree{}, errTest },
+		}
+
+		l := &Link{Delete: &yes}
+
+		if _, err := l.orphans(t.Context(), g, &Config{}, Links{}, match); !errors.Is(err, errFindOrphans) {
+			t.Fatalf("expected error %v, got %v", errFindOrphans, err)
+		}
+	})
+
+	t.Run("finds the orphans", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler:    func(github.Repo, string) (github.Tree, error) { return tree, nil },
+			CommitsHandler: commits,
+		}
+
+		l := &Link{Delete: &yes}
+		kept := Links{{To: github.File{Path: "a.txt"}}}
+
+		got, err := l.orphans(t.Context(), g, &Config{}, kept, match)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Links{{From: github.File{Path: "from/b.txt"}, To: github.File{Path: "b.txt"}}}
+		if !want.Equal(got) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+
+		if !got[0].Orphan {
+			t.Fatal("expected the link to be an orphan")
+		}
+	})
+
+	t.Run("keeps the files that only exist in to", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler:    func(github.Repo, string) (github.Tree, error) { return tree, nil },
+			CommitsHandler: commits,
+		}
+
+		l := &Link{Delete: &yes}
+		kept := Links{{To: github.File{Path: "b.txt"}}}
+
+		got, err := l.orphans(t.Context(), g, &Config{}, kept, match)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 0 {
+			t.Fatalf("expected a.txt to be kept, got %v", got)
+		}
+	})
+
+	t.Run("only checks the files without a source, once", func(t *testing.T) {
+		t.Parallel()
+
+		calls := map[string]int{}
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return tree, nil },
+			CommitsHandler: func(r github.Repo, ref, path string, limit int) ([]github.RepoCommit, error) {
+				calls[path]++
+
+				return commits(r, ref, path, limit)
+			},
+		}
+
+		c := &Config{}
+		l := &Link{Delete: &yes}
+		kept := Links{{From: github.File{Path: "from/a.txt"}, To: github.File{Path: "renamed.txt"}}}
+
+		for range 2 {
+			got, err := l.orphans(t.Context(), g, c, kept, match)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			want := Links{{From: github.File{Path: "from/b.txt"}, To: github.File{Path: "b.txt"}}}
+			if !want.Equal(got) {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+
+		if want := map[string]int{"b.txt": 1}; !maps.Equal(want, calls) {
+			t.Fatalf("expected the commits of %v, got %v", want, calls)
+		}
+	})
+
+	t.Run("fails to get the commits", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return tree, nil },
+			CommitsHandler: func(github.Repo, string, string, int) ([]github.RepoCommit, error) {
+				return nil, errTest
+			},
+		}
+
+		l := &Link{Delete: &yes}
+
+		if _, err := l.orphans(t.Context(), g, &Config{}, Links{}, match); !errors.Is(err, errFindOrphans) {
+			t.Fatalf("expected error %v, got %v", errFindOrphans, err)
+		}
+	})
+}
+
+func TestLinkNeedDelete(t *testing.T) {
+	t.Parallel()
+
+	head := github.Branch{Name: "head"}
+
+	t.Run("to is already missing", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{Orphan: true}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), gmock.Getter{}, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatal("expected no update")
+		}
+	})
+
+	t.Run("to is already deleted on head", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(*github.File) error { return github.ErrMissingFile },
+		}
+
+		l := &Link{Orphan: true, To: github.File{SHA: "sha"}}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatal("expected no update")
+		}
+	})
+
+	t.Run("fails to get to on head", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(*github.File) error { return errTest },
+		}
+
+		l := &Link{Orphan: true, To: github.File{SHA: "sha"}}
+
+		if _, err := l.NeedUpdate(t.Context(), g, head); !errors.Is(err, errTest) {
+			t.Fatalf("expected error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("to exists on head", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				if f.Ref != head.Name {
+					t.Fatalf("expected ref %q, got %q", head.Name, f.Ref)
+				}
+
+				f.SHA = "head_sha"
+
+				return nil
+			},
+		}
+
+		l := &Link{Orphan: true, To: github.File{SHA: "sha"}}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !needUpdate {
+			t.Fatal("expected an update")
+		}
+
+		if l.To.SHA != "head_sha" {
+			t.Fatalf("expected the head SHA, got %q", l.To.SHA)
+		}
+	})
+}
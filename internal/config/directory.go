@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+var errExpandDirectory = errors.New("failed to expand directory")
+
+// IsDirectory reports whether the link mirrors a whole directory, i.e. when the
+// `from` path ends with a `/`.
+func (l *Link) IsDirectory() bool {
+	return strings.HasSuffix(l.From.Path, "/")
+}
+
+// expandDirectory returns one link per file found under the `from` directory,
+// keeping their relative paths under the `to` directory.
+// Links that are not directories are returned as-is.
+func (l *Link) expandDirectory(ctx context.Context, g github.Getter, c *Config) (Links, error) {
+	if !l.IsDirectory() {
+		return Links{l}, nil
+	}
+
+	tree, err := g.GetTree(ctx, l.From.Repo, l.From.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", errExpandDirectory, l.From, err)
+	}
+
+	links := Links{}
+
+	for _, p := range tree.Blobs() {
+		rel, found := strings.CutPrefix(p, l.From.Path)
+		if !found {
+			continue
+		}
+
+		nl := *l
+		nl.From.Path = p
+		nl.To.Path = path.Join(l.To.Path, rel)
+
+		links = append(links, &nl)
+	}
+
+	if len(links) == 0 {
+		log.Warn("Directory contains no file", "from", l.From)
+	}
+
+	log.Debug("Expanded directory", "from", l.From, "links", links)
+
+	toPrefix := strings.TrimSuffix(l.To.Path, "/") + "/"
+
+	orphans, err := l.orphans(ctx, g, c, links, func(p string) (string, bool) {
+		rel, found := strings.CutPrefix(p, toPrefix)
+
+		return l.From.Path + rel, found
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(links, orphans...), nil
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestExpandDirectory(t *testing.T) {
+	t.Parallel()
+
+	tree := github.Tree{
+		Entries: []github.TreeEntry{
+			{Path: "a.txt", Type: github.TypeBlob},
+			{Path: "ci", Type: github.TypeTree},
+			{Path: "ci/b.yaml", Type: github.TypeBlob},
+			{Path: "ci/c", Type: github.TypeTree},
+			{Path: "ci/c/d.yaml", Type: github.TypeBlob},
+			{Path: "cid.txt", Type: github.TypeBlob},
+		},
+	}
+
+	t.Run("does not expand a file", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				t.Fatal("TreeHandler should not be called in this test")
+
+				return github.Tree{}, nil
+			},
+		}
+
+		l := &Link{From: github.File{Path: "ci"}}
+
+		got, err := l.expandDirectory(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0] != l {
+			t.Fatalf("expected the link itself, got %v", got)
+		}
+	})
+
+	t.Run("fails to get the tree", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				return github.Tree{}, errTest
+			},
+		}
+
+		l := &Link{From: github.File{Path: "ci/"}}
+
+		if _, err := l.expandDirectory(t.Context(), g, &Config{}); !errors.Is(err, errExpandDirectory) {
+			t.Fatalf("expected error %v, got %v", errExpandDirectory, err)
+		}
+	})
+
+	t.Run("expands the directory", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return tree, nil },
+		}
+
+		l := &Link{
+			From: github.File{Path: "ci/"},
+			To:   github.File{Path: ".github/ci/"},
+		}
+
+		got, err := l.expandDirectory(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Links{
+			{From: github.File{Path: "ci/b.yaml"}, To: github.File{Path: ".github/ci/b.yaml"}},
+			{From: github.File{Path: "ci/c/d.yaml"}, To: github.File{Path: ".github/ci/c/d.yaml"}},
+		}
+
+		if !want.Equal(got) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("finds the deleted files", func(t *testing.T) {
+		t.Parallel()
+
+		yes := true
+
+		g := gmock.Getter{
+			TreeHandler: func(r github.Repo, _ string) (github.Tree, error) {
+				if r.Repo == "to" {
+					return github.Tree{
+						Entries: []github.TreeEntry{
+							{Path: ".github/ci/b.yaml", Type: github.TypeBlob},
+							{Path: ".github/ci/e.yaml", Type: github.TypeBlob},
+							{Path: ".github/f.yaml", Type: github.TypeBlob},
+						},
+					}, nil
+				}
+
+				return tree, nil
+			},
+			CommitsHandler: func(_ github.Repo, _, path string, _ int) ([]github.RepoCommit, error) {
+				c := github.RepoCommit{SHA: "sync"}
+				c.Commit.Message = "sync\n\nSynced-From: /:ci/" + strings.TrimPrefix(path, ".github/ci/") + "@sha"
+
+				return []github.RepoCommit{c}, nil
+			},
+		}
+
+		l := &Link{
+			From:   github.File{Path: "ci/"},
+			To:     github.File{Path: ".github/ci", Repo: github.Repo{Repo: "to"}},
+			Delete: &yes,
+		}
+
+		got, err := l.expandDirectory(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 3 {
+			t.Fatalf("expected 3 links, got %v", got)
+		}
+
+		orphan := got[2]
+		if !orphan.Orphan || orphan.From.Path != "ci/e.yaml" || orphan.To.Path != ".github/ci/e.yaml" {
+			t.Fatalf("expected ci/e.yaml to be deleted, got %v", orphan)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/diff"
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+// DriftStatus is how `to` differs from what the link would write.
+type DriftStatus string
+
+const (
+	DriftIdentical DriftStatus = "identical"
+	DriftDiffers   DriftStatus = "differs"
+	DriftMissing   DriftStatus = "missing"
+	// DriftOrphan is a `to` whose `from` no longer exists.
+	DriftOrphan DriftStatus = "orphan"
+	DriftFailed DriftStatus = "failed to check"
+)
+
+const binaryDiff = "Binary files differ.\n"
+
+var errDrift = errors.New("failed to check drift")
+
+// Drift describes how `to` differs from the link.
+type Drift struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Status DriftStatus `json:"status"`
+
+	// Diff is the unified diff from the current `to` to the one the link
+	// would write.
+	Diff string `json:"diff,omitempty"`
+}
+
+// Drift compares the `to` file at ref with what the link would write in it.
+// The content is only fetched when they differ.
+func (l *Link) Drift(ctx context.Context, g github.Getter, ref string) (Drift, error) {
+	to := &github.File{Repo: l.To.Repo, Path: l.To.Path, Ref: ref}
+	d := Drift{From: l.fromString(), To: to.String()}
+
+	err := g.GetFileInfo(ctx, to)
+	missing := errors.Is(err, github.ErrMissingFile)
+
+	if err != nil && !missing {
+		return d, fmt.Errorf("%w %s: %w", errDrift, to, err)
+	}
+
+	switch {
+	case missing && l.Orphan:
+		d.Status = DriftIdentical
+
+		return d, nil
+
+	case missing:
+		d.Status = DriftMissing
+
+	case l.Orphan:
+		d.Status = DriftOrphan
+
+	default:
+		want, err := l.wantSHA(ctx, g, to)
+		if err != nil {
+			return d, fmt.Errorf("%w %s: %w", errDrift, to, err)
+		}
+
+		if want == fileSHA(*to) {
+			d.Status = DriftIdentical
+
+			return d, nil
+		}
+
+		d.Status = DriftDiffers
+	}
+
+	if d.Diff, err = l.diff(ctx, g, to); err != nil {
+		return d, fmt.Errorf("%w %s: %w", errDrift, to, err)
+	}
+
+	return d, nil
+}
+
+// diff returns the unified diff from `to` to what the link would write in it.
+func (l *Link) diff(ctx context.Context, g github.Getter, to *github.File) (string, error) {
+	if to.SHA != "" && to.Content == "" {
+		if err := g.GetFile(ctx, to); err != nil {
+			return "", fmt.Errorf("%w %s: %w", errMissingTo, to, err)
+		}
+	}
+
+	want := ""
+
+	if !l.Orphan {
+		if err := l.loadFrom(ctx, g); err != nil {
+			return "", err
+		}
+
+		var err error
+		if want, err = l.content(to.Content); err != nil {
+			return "", err
+		}
+	}
+
+	if isBinary(to.Content) || isBinary(want) {
+		return binaryDiff, nil
+	}
+
+	return diff.Unified(to.String(), l.fromString(), to.Content, want), nil
+}
+
+// Drifts is the drift report of all the links.
+type Drifts []Drift
+
+// Summary counts the links per status, e.g. `2 identical, 1 differs`.
+func (d Drifts) Summary() string {
+	counts := map[DriftStatus]int{}
+	for _, drift := range d {
+		counts[drift.Status]++
+	}
+
+	parts := []string{}
+
+	for _, s := range []DriftStatus{DriftIdentical, DriftDiffers, DriftMissing, DriftOrphan, DriftFailed} {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "no link"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Markdown renders a table of the links, followed by their diffs.
+func (d Drifts) Markdown() string {
+	out := strings.Builder{}
+
+	fmt.Fprintf(&out, "# Drift report\n\n%s.\n\n", d.Summary())
+	out.WriteString("| From | To | Status |\n| --- | --- | --- |\n")
+
+	for _, drift := range d {
+		fmt.Fprintf(&out, "| `%s` | `%s` | %s |\n", drift.From, drift.To, drift.Status)
+	}
+
+	for _, drift := range d {
+		if drift.Diff != "" {
+			fmt.Fprintf(&out, "\n## `%s`\n\n%s", drift.To, drift.MarkdownDiff())
+		}
+	}
+
+	return out.String()
+}
+
+// MarkdownDiff renders the diff in a code block.
+func (d Drift) MarkdownDiff() string {
+	// NOTE: The fence must be longer than any in the diff.
+	fence := "```"
+	for strings.Contains(d.Diff, fence) {
+		fence += "`"
+	}
+
+	return fmt.Sprintf("%sdiff\n%s%s\n", fence, d.Diff, fence)
+}
+
+// JSON renders the links as an indented JSON array.
+func (d Drifts) JSON() (string, error) {
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the drifts: %w", err)
+	}
+
+	return string(out) + "\n", nil
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestLinkDrift(t *testing.T) {
+	t.Parallel()
+
+	to := github.File{Repo: github.Repo{Owner: github.User{Login: "o"}, Repo: "r"}, Path: "to"}
+
+	// mkGetter returns a Getter where `to` has the content, or is missing if
+	// it's empty.
+	mkGetter := func(t *testing.T, content string) gmock.Getter {
+		t.Helper()
+
+		return gmock.Getter{
+			InfoHandler: func(f *github.File) error {
+				if f.Ref != "main" {
+					t.Fatalf("want ref 'main', got %q", f.Ref)
+				}
+
+				if content == "" {
+					return github.ErrMissingFile
+				}
+
+				f.SHA = github.BlobSHA(content)
+
+				return nil
+			},
+			FileHandler: func(f *github.File) error {
+				f.Content = content
+
+				return nil
+			},
+		}
+	}
+
+	t.Run("fails to get to", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{InfoHandler: func(*github.File) error { return errTest }}
+		l := &Link{From: github.File{Content: "a\n"}, To: to}
+
+		if _, err := l.Drift(t.Context(), g, "main"); !errors.Is(err, errDrift) {
+			t.Fatalf("want error %v, got %v", errDrift, err)
+		}
+	})
+
+	t.Run("is identical", func(t *testing.T) {
+		t.Parallel()
+
+		g := mkGetter(t, "a\n")
+		g.FileHandler = func(*github.File) error {
+			t.Fatal("FileHandler should not be called in this test")
+
+			return nil
+		}
+
+		l := &Link{From: github.File{Content: "a\n"}, To: to}
+
+		got, err := l.Drift(t.Context(), g, "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got.Status != DriftIdentical || got.Diff != "" {
+			t.Fatalf("want identical without diff, got %+v", got)
+		}
+	})
+
+	t.Run("differs", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: github.File{Content: "b\n"}, To: to}
+
+		got, err := l.Drift(t.Context(), mkGetter(t, "a\n"), "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		want := Drift{
+			From:   "/:@",
+			To:     "o/r:to@main",
+			Status: DriftDiffers,
+			Diff:   "--- o/r:to@main\n+++ /:@\n@@ -1,1 +1,1 @@\n-a\n+b\n",
+		}
+
+		if got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("is missing", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: github.File{Content: "b\n"}, To: to}
+
+		got, err := l.Drift(t.Context(), mkGetter(t, ""), "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got.Status != DriftMissing || !strings.HasSuffix(got.Diff, "@@ -0,0 +1,1 @@\n+b\n") {
+			t.Fatalf("want missing with an addition, got %+v", got)
+		}
+	})
+
+	t.Run("is an orphan", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{To: to, Orphan: true}
+
+		got, err := l.Drift(t.Context(), mkGetter(t, "a\n"), "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got.Status != DriftOrphan || !strings.HasSuffix(got.Diff, "@@ -1,1 +0,0 @@\n-a\n") {
+			t.Fatalf("want orphan with a deletion, got %+v", got)
+		}
+	})
+
+	t.Run("is an already deleted orphan", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{To: to, Orphan: true}
+
+		got, err := l.Drift(t.Context(), mkGetter(t, ""), "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got.Status != DriftIdentical {
+			t.Fatalf("want identical, got %+v", got)
+		}
+	})
+
+	t.Run("differs in binary", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: github.File{Content: "b\x00"}, To: to}
+
+		got, err := l.Drift(t.Context(), mkGetter(t, "a\x00"), "main")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got.Diff != binaryDiff {
+			t.Fatalf("want a binary diff, got %+v", got)
+		}
+	})
+}
+
+func TestDrifts(t *testing.T) {
+	t.Parallel()
+
+	d := Drifts{
+		{From: "f1", To: "t1", Status: DriftIdentical},
+		{From: "f2", To: "t2", Status: DriftDiffers, Diff: "-a\n+```\n"},
+		{From: "f3", To: "t3", Status: DriftDiffers, Diff: "-a\n+b\n"},
+	}
+
+	if got, want := d.Summary(), "1 identical, 2 differs"; got != want {
+		t.Fatalf("want summary %q, got %q", want, got)
+	}
+
+	if got, want := (Drifts{}).Summary(), "no link"; got != want {
+		t.Fatalf("want summary %q, got %q", want, got)
+	}
+
+	wantMarkdown := "# Drift report\n\n1 identical, 2 differs.\n\n" +
+		"| From | To | Status |\n| --- | --- | --- |\n" +
+		"| `f1` | `t1` | identical |\n" +
+		"| `f2` | `t2` | differs |\n" +
+		"| `f3` | `t3` | differs |\n" +
+		"\n## `t2`\n\n````diff\n-a\n+```\n````\n" +
+		"\n## `t3`\n\n```diff\n-a\n+b\n```\n"
+
+	if got := d.Markdown(); got != wantMarkdown {
+		t.Fatalf("want markdown\n%s\ngot\n%s", wantMarkdown, got)
+	}
+
+	gotJSON, err := d[:1].JSON()
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	wantJSON := "[\n  {\n    \"from\": \"f1\",\n    \"to\": \"t1\",\n    \"status\": \"identical\"\n  }\n]\n"
+	if gotJSON != wantJSON {
+		t.Fatalf("want JSON\n%s\ngot\n%s", wantJSON, gotJSON)
+	}
+}
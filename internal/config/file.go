@@ -9,6 +9,15 @@ import (
 	"github.com/nobe4/action-ln/internal/github"
 )
 
+// Refs can be branches (e.g. `release/v1.2`), tags, commit SHAs or semver
+// constraints (e.g. `>=1.2, <2 || ^3`), see Links.ResolveRefs.
+// In GitHub's URLs, the ref can't contain a slash, as it would be ambiguous
+// with the path.
+const (
+	refPattern    = `[\w./^~<>=!|*, -]+`
+	urlRefPattern = `[\w.-]+`
+)
+
 var ErrInvalidFileType = errors.New("invalid file type")
 
 func (c *Config) parseFile(rawFile any) ([]github.File, error) {
@@ -53,9 +62,14 @@ func (*Config) parseMap(rawFile map[string]any) ([]github.File, error) {
 		getMapKey(rawFile, "repo"),
 	)
 
+	f.Repo.Forge = getMapKey(rawFile, "forge")
 	f.Path = getMapKey(rawFile, "path")
 	f.Ref = getMapKey(rawFile, "ref")
 
+	if vars, ok := rawFile["vars"].(map[string]any); ok {
+		f.Vars = vars
+	}
+
 	return []github.File{f}, nil
 }
 
@@ -66,9 +80,27 @@ func (*Config) parseMap(rawFile map[string]any) ([]github.File, error) {
 //
 //nolint:revive // This function doesn't need to be simplified.
 func (*Config) parseString(s string) ([]github.File, error) {
+	// 'gitlab:group/subgroup/project:path/to/file@ref'
+	// The path and ref are optional, as in 'owner/repo:path/to/file@ref'.
+	if m := regexp.
+		MustCompile(`^gitlab:(?P<owner>[\w.-]+(?:/[\w.-]+)*)/(?P<repo>[\w.-]+):(?P<path>[^@]*)(?:@(?P<ref>` + refPattern + `))?$`).
+		FindStringSubmatch(s); len(m) > 0 {
+		return []github.File{
+			{
+				Repo: github.Repo{
+					Owner: github.User{Login: m[1]},
+					Repo:  m[2],
+					Forge: github.ForgeGitLab,
+				},
+				Path: m[3],
+				Ref:  m[4],
+			},
+		}, nil
+	}
+
 	// 'https://github.com/owner/repo/blob/ref/path/to/file'
 	if m := regexp.
-		MustCompile(`^https://github.com/(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w-]+)/(?P<path>.+)$`).
+		MustCompile(`^https://github.com/(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>` + urlRefPattern + `)/(?P<path>.+)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -84,7 +116,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo/blob/ref/path/to/file'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>[\w-]+)/(?P<path>.+)$`).
+		MustCompile(`^(?P<owner>[\w-]+)/(?P<repo>[\w-]+)/blob/(?P<ref>` + urlRefPattern + `)/(?P<path>.+)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -100,7 +132,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo:path/to/file@ref'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):(?P<path>[^@]+)@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):(?P<path>[^@]+)@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -131,7 +163,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'owner/repo:@ref'
 	if m := regexp.
-		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<owner>[\w-]*)/(?P<repo>[\w-]*):@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -160,7 +192,7 @@ func (*Config) parseString(s string) ([]github.File, error) {
 
 	// 'path/to/file@ref'
 	if m := regexp.
-		MustCompile(`^(?P<path>[^@]+)@(?P<ref>[\w-]+)$`).
+		MustCompile(`^(?P<path>[^@]+)@(?P<ref>` + refPattern + `)$`).
 		FindStringSubmatch(s); len(m) > 0 {
 		return []github.File{
 			{
@@ -106,6 +106,11 @@ func TestParseFile(t *testing.T) {
 			},
 		},
 
+		{
+			input: map[string]any{"path": "path", "vars": map[string]any{"name": "value"}},
+			want:  []github.File{{Path: "path", Vars: map[string]any{"name": "value"}}},
+		},
+
 		{
 			input: map[string]any{"repo": "repo/owner", "path": "path"},
 			want: []github.File{
@@ -272,6 +277,118 @@ func TestParseFile(t *testing.T) {
 			},
 		},
 
+		{
+			input: "owner/repo:path@release/v1.2",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Path: "path",
+					Ref:  "release/v1.2",
+				},
+			},
+		},
+
+		{
+			input: "owner/repo:path@>=1.2, <2 || ^3",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Path: "path",
+					Ref:  ">=1.2, <2 || ^3",
+				},
+			},
+		},
+
+		{
+			input: "owner/repo:@v2.x",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Ref: "v2.x",
+				},
+			},
+		},
+
+		{
+			input: map[string]any{"forge": "gitlab", "owner": "group/subgroup", "repo": "project", "path": "path"},
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "group/subgroup"},
+						Repo:  "project",
+						Forge: github.ForgeGitLab,
+					},
+					Path: "path",
+				},
+			},
+		},
+
+		{
+			input: "gitlab:group/subgroup/project:path@v1.2.3",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "group/subgroup"},
+						Repo:  "project",
+						Forge: github.ForgeGitLab,
+					},
+					Path: "path",
+					Ref:  "v1.2.3",
+				},
+			},
+		},
+
+		{
+			input: "gitlab:group/project:path",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "group"},
+						Repo:  "project",
+						Forge: github.ForgeGitLab,
+					},
+					Path: "path",
+				},
+			},
+		},
+
+		{
+			input: "gitlab:group/project:@main",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "group"},
+						Repo:  "project",
+						Forge: github.ForgeGitLab,
+					},
+					Ref: "main",
+				},
+			},
+		},
+
+		{
+			input: "owner/repo/blob/v1.2.3/path",
+			want: []github.File{
+				{
+					Repo: github.Repo{
+						Owner: github.User{Login: "owner"},
+						Repo:  "repo",
+					},
+					Path: "path",
+					Ref:  "v1.2.3",
+				},
+			},
+		},
+
 		{
 			input: "owner/:",
 			want: []github.File{
@@ -309,6 +426,11 @@ func TestParseFile(t *testing.T) {
 			want:  []github.File{{Path: complexPath, Ref: "ref"}},
 		},
 
+		{
+			input: "path@latest-release",
+			want:  []github.File{{Path: "path", Ref: "latest-release"}},
+		},
+
 		{
 			input: "path",
 			want:  []github.File{{Path: "path"}},
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const globStar = "**"
+
+var errExpandGlob = errors.New("failed to expand glob")
+
+// isGlob reports whether the path contains any glob pattern.
+// Templated paths are ignored, as they are only resolved later.
+func isGlob(p string) bool {
+	return !strings.Contains(p, "{{") && strings.ContainsAny(p, "*?[")
+}
+
+// matchGlob extends path.Match with `**`, which matches zero or more
+// directories.
+func matchGlob(pattern, name string) bool {
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == globStar {
+			for i := 0; i <= len(name); i++ {
+				if matchParts(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// expandGlob returns one link per file matching the `from` path.
+// Links without a glob are returned as-is.
+func (l *Link) expandGlob(ctx context.Context, g github.Getter, c *Config) (Links, error) {
+	if !isGlob(l.From.Path) {
+		return Links{l}, nil
+	}
+
+	tree, err := g.GetTree(ctx, l.From.Repo, l.From.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", errExpandGlob, l.From, err)
+	}
+
+	links := Links{}
+
+	for _, p := range tree.Blobs() {
+		if !matchGlob(l.From.Path, p) {
+			continue
+		}
+
+		nl := *l
+		nl.From.Path = p
+
+		// The `to` inherited the glob from the `from` in combineLinks.
+		if nl.To.Path == l.From.Path {
+			nl.To.Path = p
+		}
+
+		links = append(links, &nl)
+	}
+
+	if len(links) == 0 {
+		log.Warn("Glob matched no file", "from", l.From)
+	}
+
+	log.Debug("Expanded glob", "from", l.From, "links", links)
+
+	if l.To.Path != l.From.Path {
+		if l.ShouldDelete() {
+			log.Warn("Deleting files is only supported when `to` mirrors the glob", "link", l)
+		}
+
+		return links, nil
+	}
+
+	orphans, err := l.orphans(ctx, g, c, links, func(p string) (string, bool) {
+		return p, matchGlob(l.From.Path, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(links, orphans...), nil
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestIsGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{},
+		{path: "a.txt"},
+		{path: "a/b/c.txt"},
+		{path: "{{ .Link.From.Path }}*"},
+		{path: "*.txt", want: true},
+		{path: "a/?.txt", want: true},
+		{path: "a/[ab].txt", want: true},
+		{path: "a/**/b.txt", want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isGlob(test.path); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.txt", name: "a.txt", want: true},
+		{pattern: "*.txt", name: "a.md"},
+		{pattern: "*.txt", name: "a/b.txt"},
+		{pattern: "a/*.txt", name: "a/b.txt", want: true},
+		{pattern: "a/*", name: "a/b/c.txt"},
+		{pattern: "**/*.txt", name: "a.txt", want: true},
+		{pattern: "**/*.txt", name: "a/b/c.txt", want: true},
+		{pattern: "a/**", name: "a/b/c.txt", want: true},
+		{pattern: "a/**", name: "b/c.txt"},
+		{pattern: "a/**/c.txt", name: "a/c.txt", want: true},
+		{pattern: "a/**/c.txt", name: "a/b/d/c.txt", want: true},
+		{pattern: "a/**/c.txt", name: "a/b/d/e.txt"},
+		{pattern: "[", name: "["},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := matchGlob(test.pattern, test.name); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	t.Parallel()
+
+	tree := github.Tree{
+		Entries: []github.TreeEntry{
+			{Path: "a.txt", Type: github.TypeBlob},
+			{Path: "b", Type: github.TypeTree},
+			{Path: "b/c.txt", Type: github.TypeBlob},
+		},
+	}
+
+	t.Run("does not expand a regular path", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				t.Fatal("TreeHandler should not be called in this test")
+
+				return github.Tree{}, nil
+			},
+		}
+
+		l := &Link{From: github.File{Path: "a.txt"}}
+
+		got, err := l.expandGlob(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0] != l {
+			t.Fatalf("expected the link itself, got %v", got)
+		}
+	})
+
+	t.Run("fails to get the tree", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) {
+				return github.Tree{}, errTest
+			},
+		}
+
+		l := &Link{From: github.File{Path: "*.txt"}}
+
+		if _, err := l.expandGlob(t.Context(), g, &Config{}); !errors.Is(err, errExpandGlob) {
+			t.Fatalf("expected error %v, got %v", errExpandGlob, err)
+		}
+	})
+
+	t.Run("expands the glob", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(_ github.Repo, ref string) (github.Tree, error) {
+				if ref != "main" {
+					t.Fatalf("expected ref 'main', got %q", ref)
+				}
+
+				return tree, nil
+			},
+		}
+
+		l := &Link{
+			From: github.File{Path: "**/*.txt", Ref: "main"},
+			To:   github.File{Path: "to"},
+		}
+
+		got, err := l.expandGlob(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Links{
+			{From: github.File{Path: "a.txt", Ref: "main"}, To: github.File{Path: "to"}},
+			{From: github.File{Path: "b/c.txt", Ref: "main"}, To: github.File{Path: "to"}},
+		}
+
+		if !want.Equal(got) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("replaces the inherited to", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TreeHandler: func(github.Repo, string) (github.Tree, error) { return tree, nil },
+		}
+
+		l := &Link{
+			From: github.File{Path: "b/*"},
+			To:   github.File{Path: "b/*"},
+		}
+
+		got, err := l.expandGlob(t.Context(), g, &Config{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Links{
+			{From: github.File{Path: "b/c.txt"}, To: github.File{Path: "b/c.txt"}},
+		}
+
+		if !want.Equal(got) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+}
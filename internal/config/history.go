@@ -0,0 +1,161 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	// syncedFromTrailer records, in the commits written by action-ln, the
+	// commit of each `from` that was synced.
+	// E.g. `Synced-From: owner/repo:path/to/file@<sha>`.
+	syncedFromTrailer = "Synced-From"
+
+	// changesLimit caps the number of upstream commits listed per link.
+	changesLimit = 100
+
+	// syncedLimit caps the number of `to` commits searched for the trailer.
+	syncedLimit = 20
+)
+
+// History fills the upstream history of the links, see Link.history.
+// Failing to get it is not fatal, as it's only informative.
+func (l *Links) History(ctx context.Context, g github.Getter) {
+	for _, link := range *l {
+		if err := link.history(ctx, g); err != nil {
+			log.Warn("Failed to get the history", "link", link, "err", err)
+		}
+	}
+}
+
+// Changes returns the upstream commits of all the links, without duplicates.
+func (l Links) Changes() []github.RepoCommit {
+	changes := []github.RepoCommit{}
+	seen := map[string]bool{}
+
+	for _, link := range l {
+		for _, c := range link.Changes {
+			if seen[c.SHA] {
+				continue
+			}
+
+			seen[c.SHA] = true
+
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// history sets the last commit that changed `from`, and the commits that
+// changed it since the one recorded in the last sync of `to`.
+// It is only done once per link.
+func (l *Link) history(ctx context.Context, g github.Getter) error {
+	if l.Orphan || len(l.Sources) > 0 || l.SourceSHA != "" {
+		return nil
+	}
+
+	commits, err := g.GetCommits(ctx, l.From.Repo, l.From.Ref, l.From.Path, changesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get the commits of %s: %w", l.From, err)
+	}
+
+	if len(commits) == 0 {
+		return nil
+	}
+
+	l.SourceSHA = commits[0].SHA
+
+	synced, err := l.syncedSHA(ctx, g)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Without a previous sync, there's no range to list.
+	if synced == "" {
+		log.Debug("No previous sync found", "link", l)
+
+		return nil
+	}
+
+	for _, c := range commits {
+		if c.SHA == synced {
+			break
+		}
+
+		l.Changes = append(l.Changes, c)
+	}
+
+	log.Debug("Found upstream changes", "link", l, "synced", synced, "changes", len(l.Changes))
+
+	return nil
+}
+
+// syncedSHA returns the commit of `from` recorded in the last commits of `to`.
+func (l *Link) syncedSHA(ctx context.Context, g github.Getter) (string, error) {
+	commits, err := g.GetCommits(ctx, l.To.Repo, l.To.Ref, l.To.Path, syncedLimit)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the commits of %s: %w", l.To, err)
+	}
+
+	prefix := l.syncedFrom("")
+
+	for _, c := range commits {
+		for line := range strings.Lines(c.Commit.Message) {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+			if ok && key == syncedFromTrailer && strings.HasPrefix(value, prefix) {
+				return strings.TrimPrefix(value, prefix), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// syncedSHA caches Link.syncedSHA per `to` and `from`, as the same files
+// can be checked by several links of the config.
+func (c *Config) syncedSHA(ctx context.Context, g github.Getter, l *Link) (string, error) {
+	if c.synced == nil {
+		c.synced = map[string]string{}
+	}
+
+	key := l.To.String() + " " + l.syncedFrom("")
+
+	if synced, ok := c.synced[key]; ok {
+		return synced, nil
+	}
+
+	synced, err := l.syncedSHA(ctx, g)
+	if err != nil {
+		return "", err
+	}
+
+	c.synced[key] = synced
+
+	return synced, nil
+}
+
+// syncedFrom returns the trailer value for `from` at sha.
+func (l *Link) syncedFrom(sha string) string {
+	return fmt.Sprintf("%s:%s@%s", l.From.Repo, l.From.Path, sha)
+}
+
+// trailers returns the commit trailers recording the synced `from`.
+func (l Links) trailers() string {
+	trailers := strings.Builder{}
+
+	for _, link := range l {
+		if link.SourceSHA == "" {
+			continue
+		}
+
+		fmt.Fprintf(&trailers, "%s: %s\n", syncedFromTrailer, link.syncedFrom(link.SourceSHA))
+	}
+
+	return trailers.String()
+}
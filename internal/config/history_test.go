@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func mkCommit(sha, msg string) github.RepoCommit {
+	c := github.RepoCommit{SHA: sha}
+	c.Commit.Message = msg
+
+	return c
+}
+
+func TestLinkHistory(t *testing.T) {
+	t.Parallel()
+
+	from := github.File{Repo: github.Repo{Owner: github.User{Login: "o"}, Repo: "r"}, Path: "a"}
+	to := github.File{Repo: github.Repo{Owner: github.User{Login: "o"}, Repo: "t"}, Path: "b"}
+
+	upstream := []github.RepoCommit{
+		mkCommit("3", "third"),
+		mkCommit("2", "second"),
+		mkCommit("1", "first"),
+	}
+
+	mkGetter := func(synced []github.RepoCommit) gmock.Getter {
+		return gmock.Getter{
+			CommitsHandler: func(r github.Repo, _, _ string, _ int) ([]github.RepoCommit, error) {
+				if r.Equal(from.Repo) {
+					return upstream, nil
+				}
+
+				return synced, nil
+			},
+		}
+	}
+
+	t.Run("skips the orphans", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: from, To: to, Orphan: true}
+
+		if err := l.history(t.Context(), gmock.Getter{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.SourceSHA != "" {
+			t.Fatalf("want no source SHA, got %q", l.SourceSHA)
+		}
+	})
+
+	t.Run("fails to get the commits", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			CommitsHandler: func(github.Repo, string, string, int) ([]github.RepoCommit, error) {
+				return nil, errTest
+			},
+		}
+
+		l := &Link{From: from, To: to}
+
+		if err := l.history(t.Context(), g); !errors.Is(err, errTest) {
+			t.Fatalf("want error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("has no previous sync", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: from, To: to}
+
+		if err := l.history(t.Context(), mkGetter(nil)); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.SourceSHA != "3" || len(l.Changes) != 0 {
+			t.Fatalf("want source SHA '3' and no changes, got %q and %v", l.SourceSHA, l.Changes)
+		}
+	})
+
+	t.Run("lists the changes since the last sync", func(t *testing.T) {
+		t.Parallel()
+
+		synced := []github.RepoCommit{
+			mkCommit("x", "manual change"),
+			mkCommit("y", "auto(ln): update links\n\nSynced-From: o/r:other@2\nSynced-From: o/r:a@1\n"),
+		}
+
+		l := &Link{From: from, To: to}
+
+		if err := l.history(t.Context(), mkGetter(synced)); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if len(l.Changes) != 2 || l.Changes[0].SHA != "3" || l.Changes[1].SHA != "2" {
+			t.Fatalf("want changes 3 and 2, got %v", l.Changes)
+		}
+	})
+}
+
+func TestLinksChanges(t *testing.T) {
+	t.Parallel()
+
+	l := Links{
+		{Changes: []github.RepoCommit{{SHA: "2"}, {SHA: "1"}}},
+		{Changes: []github.RepoCommit{{SHA: "3"}, {SHA: "1"}}},
+	}
+
+	got := l.Changes()
+
+	if len(got) != 3 || got[0].SHA != "2" || got[1].SHA != "1" || got[2].SHA != "3" {
+		t.Fatalf("want changes 2, 1 and 3, got %v", got)
+	}
+}
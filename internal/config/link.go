@@ -13,10 +13,6 @@ import (
 )
 
 const (
-	commitMsgTemplate = `auto(ln): update {{ .Data.To.Path }}
-
-Source: {{ .Data.From.HTMLURL }}
-`
 	linkStringPartCount = 2
 )
 
@@ -34,7 +30,59 @@ type Link struct {
 	From github.File `json:"from" yaml:"from"`
 	To   github.File `json:"to"   yaml:"to"`
 
+	// Delete removes `to` once `from` no longer exists.
+	// It applies to the glob and directory links.
+	Delete *bool `json:"delete,omitempty" yaml:"delete,omitempty"`
+
+	// Branch is the head branch where `to` is written.
+	// It is a template, see Link.Format.
+	Branch string `json:"branch" yaml:"branch"`
+
+	// Rebase resets the head branch onto base when it is behind.
+	Rebase *bool `json:"rebase,omitempty" yaml:"rebase,omitempty"`
+
+	// Template renders the content of `from` as a template, see Link.render.
+	Template *bool `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Vars are available to the templates, on top of the `to` ones.
+	Vars map[string]any `json:"vars,omitempty" yaml:"vars,omitempty"`
+
+	// Transforms are applied, in order, to the content of `from`.
+	Transforms []Transform `json:"transform,omitempty" yaml:"transform,omitempty"`
+
+	// Section only syncs the block between the markers in `to`.
+	Section *Section `json:"section,omitempty" yaml:"section,omitempty"`
+
+	// Merge deep-merges `from` into `to`.
+	Merge *Merge `json:"merge,omitempty" yaml:"merge,omitempty"`
+
+	// Concat joins all the Sources into `to`, see Links.Concat.
+	Concat  *Concat       `json:"concat,omitempty"  yaml:"concat,omitempty"`
+	Sources []github.File `json:"sources,omitempty" yaml:"sources,omitempty"`
+
+	// Pull configures the pull request the link is part of.
+	Pull Pull `json:"pull" yaml:"pull"`
+
+	// Commit configures the commit the link is part of.
+	Commit Commit `json:"commit" yaml:"commit"`
+
+	// RequestedRef is the `from` ref as configured, when it was resolved to
+	// another one, see Links.ResolveRefs.
+	RequestedRef string `json:"requested_ref,omitempty" yaml:"requested_ref,omitempty"`
+
+	// SourceSHA is the last commit that changed `from`, see Link.history.
+	SourceSHA string `json:"source_sha,omitempty" yaml:"source_sha,omitempty"`
+
+	// Changes are the commits that changed `from` since it was last synced.
+	Changes []github.RepoCommit `json:"changes,omitempty" yaml:"changes,omitempty"`
+
+	// Orphan is set when `from` no longer exists and `to` should be deleted.
+	Orphan bool `json:"orphan" yaml:"orphan"`
+
 	Status Status `json:"status" yaml:"status"`
+
+	// headContent is the content of `to` on the head branch, see NeedUpdate.
+	headContent string
 }
 
 type Status string
@@ -44,24 +92,175 @@ const (
 	StatusFailedToUpdate  Status = "failed to update"
 	StatusUpdateNotNeeded Status = "update not needed"
 	StatusUpdated         Status = "updated"
+	StatusDeleted         Status = "deleted"
 )
 
 // The parsing can be done from a couple of various format, see ParseFile.
 type RawLink struct {
-	From any `yaml:"from"`
-	To   any `yaml:"to"`
+	From   any    `yaml:"from"`
+	To     any    `yaml:"to"`
+	Delete *bool  `yaml:"delete"`
+	Branch string `yaml:"branch"`
+	Rebase *bool  `yaml:"rebase"`
+	Pull   Pull   `yaml:"pull"`
+	Commit Commit `yaml:"commit"`
+
+	Template   *bool          `yaml:"template"`
+	Vars       map[string]any `yaml:"vars"`
+	Transforms []Transform    `yaml:"transform"`
+	Section    *Section       `yaml:"section"`
+	Merge      *Merge         `yaml:"merge"`
+	Concat     *Concat        `yaml:"concat"`
+}
+
+// groupOption returns the name of the first option set that configures the
+// group rather than the file, or "" if there is none.
+func (r RawLink) groupOption() string {
+	switch {
+	case r.Branch != "":
+		return "branch"
+	case r.Rebase != nil:
+		return "rebase"
+	case r.Pull.Draft != nil || !r.Pull.equal(Pull{}):
+		return "pull"
+	case r.Commit != (Commit{}):
+		return "commit"
+	}
+
+	return ""
+}
+
+// applyOptions sets all the non-file fields of the link.
+func (r RawLink) applyOptions(l *Link) {
+	l.Delete = r.Delete
+	l.Branch = r.Branch
+	l.Rebase = r.Rebase
+	l.Template = r.Template
+	l.Vars = r.Vars
+	l.Transforms = r.Transforms
+	l.Section = r.Section
+	l.Merge = r.Merge
+	l.Concat = r.Concat
+	l.Pull = r.Pull
+	l.Commit = r.Commit
 }
 
 func (l *Link) String() string {
-	return fmt.Sprintf("%s -> %s", l.From, l.To)
+	return fmt.Sprintf("%s -> %s", l.fromString(), l.To)
+}
+
+// fromString prints `from`, or all the sources when there are several.
+func (l *Link) fromString() string {
+	if len(l.Sources) > 1 {
+		froms := make([]string, 0, len(l.Sources))
+		for _, s := range l.Sources {
+			froms = append(froms, s.String())
+		}
+
+		return strings.Join(froms, " + ")
+	}
+
+	return l.From.String()
 }
 
 func (l *Link) Equal(other *Link) bool {
 	return l.From.Equal(other.From) && l.To.Equal(other.To)
 }
 
+// Format renders the branch with the formatter, so it can use the same data
+// as the pull request.
+func (l *Link) Format(f format.Formatter) error {
+	branch, err := f.FormatText(l.Branch, l)
+	if err != nil {
+		return fmt.Errorf("%w to %q: %w", errFailTemplate, "Branch", err)
+	}
+
+	l.Branch = branch
+
+	return nil
+}
+
+func (l *Link) validate() error {
+	if err := validateTextTemplate("branch", l.Branch); err != nil {
+		return err
+	}
+
+	if err := l.Pull.validate(); err != nil {
+		return err
+	}
+
+	for _, t := range l.Transforms {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+
+	if l.Section != nil {
+		if err := l.Section.validate(); err != nil {
+			return err
+		}
+	}
+
+	if l.Merge != nil {
+		if l.Section != nil {
+			return fmt.Errorf("%w: cannot be used with a section", errInvalidMerge)
+		}
+
+		if err := l.Merge.validate(); err != nil {
+			return err
+		}
+	}
+
+	if l.Concat != nil {
+		if err := l.Concat.validate(); err != nil {
+			return err
+		}
+	}
+
+	return l.Commit.validate()
+}
+
+// ShouldDelete reports whether `to` must be deleted once `from` no longer
+// exists.
+func (l *Link) ShouldDelete() bool {
+	return l.Delete != nil && *l.Delete
+}
+
+// content returns what `to` should contain, given its current content.
+func (l *Link) content(current string) (string, error) {
+	if (l.Section != nil || l.Merge != nil) && (isBinary(current) || isBinary(l.From.Content)) {
+		return "", fmt.Errorf("%w: %q", errBinary, l)
+	}
+
+	switch {
+	case l.Section != nil:
+		return l.Section.replace(current, l.From.Content)
+
+	case l.Merge != nil:
+		return l.Merge.merge(current, l.From.Content)
+
+	default:
+		return l.From.Content, nil
+	}
+}
+
+// ShouldRebase reports whether the head branch is reset onto base when it is
+// behind.
+func (l *Link) ShouldRebase() bool {
+	return l.Rebase != nil && *l.Rebase
+}
+
 func (l *Link) NeedUpdate(ctx context.Context, g github.Getter, head github.Branch) (bool, error) {
-	if l.From.Content == l.To.Content {
+	if l.Orphan {
+		return l.needDelete(ctx, g, head)
+	}
+
+	want, err := l.wantSHA(ctx, g, &l.To)
+	if err != nil {
+		return false, err
+	}
+
+	if want == fileSHA(l.To) {
 		log.Debug("Content is the same", "from", l.From, "to", l.To)
 
 		return false, nil
@@ -75,17 +274,25 @@ func (l *Link) NeedUpdate(ctx context.Context, g github.Getter, head github.Bran
 
 	log.Debug("Checking head content", "from", l.From, "to@head", headTo)
 
-	if err := g.GetFile(ctx, headTo); err != nil {
+	if err := g.GetFileInfo(ctx, headTo); err != nil {
 		if errors.Is(err, github.ErrMissingFile) {
 			log.Warn("File is missing", "to@head", headTo)
 
+			l.headContent = ""
+
 			return true, nil
 		}
 
 		return false, fmt.Errorf("failed to get to@head %s: %w", headTo, err)
 	}
 
-	if l.From.Content == headTo.Content {
+	if want, err = l.wantSHA(ctx, g, headTo); err != nil {
+		return false, err
+	}
+
+	l.headContent = headTo.Content
+
+	if want == fileSHA(*headTo) {
 		log.Debug("Content is the same", "from", l.From, "to@head", headTo)
 
 		return false, nil
@@ -96,24 +303,45 @@ func (l *Link) NeedUpdate(ctx context.Context, g github.Getter, head github.Bran
 	return true, nil
 }
 
-func (l *Link) Update(ctx context.Context, g github.Updater, f format.Formatter, head github.Branch) error {
+// treeEntry prepares the tree entry that updates the `to` file on the head
+// branch. The mode of an existing file is kept.
+func (l *Link) treeEntry(ctx context.Context, g github.GetterUpdater, mode string) (github.TreeEntry, error) {
 	log.Info("Processing link", "link", l)
 
-	l.To.Content = l.From.Content
+	if mode == "" {
+		mode = github.ModeFile
+	}
+
+	entry := github.TreeEntry{
+		Path: l.To.Path,
+		Mode: mode,
+		Type: github.TypeBlob,
+	}
+
+	// NOTE: an entry without SHA deletes the file.
+	if l.Orphan {
+		return entry, nil
+	}
+
+	if err := l.loadFrom(ctx, g); err != nil {
+		return github.TreeEntry{}, err
+	}
 
-	msg, err := f.Format(commitMsgTemplate, l)
+	content, err := l.content(l.headContent)
 	if err != nil {
-		return fmt.Errorf("failed to format the commit message: %w", err)
+		return github.TreeEntry{}, err
 	}
 
-	newTo, err := g.UpdateFile(ctx, l.To, head.Name, msg)
+	l.To.Content = content
+
+	sha, err := g.CreateBlob(ctx, l.To.Repo, l.To.Content)
 	if err != nil {
-		return fmt.Errorf("failed to update file: %w", err)
+		return github.TreeEntry{}, fmt.Errorf("failed to create blob: %w", err)
 	}
 
-	log.Info("Updated file", "new to", newTo)
+	entry.SHA = sha
 
-	return nil
+	return entry, nil
 }
 
 func (c *Config) ParseLinkString(s string) (Link, error) {
@@ -140,7 +368,19 @@ func (c *Config) ParseLinkString(s string) (Link, error) {
 	return Link{From: from[0], To: to[0]}, nil
 }
 
-func (l *Link) populate(ctx context.Context, g github.Getter) error {
+func (l *Link) populate(ctx context.Context, g github.Getter, c *Config) error {
+	if l.Orphan {
+		return l.populateTo(ctx, g)
+	}
+
+	if len(l.Sources) > 0 {
+		if err := l.populateSources(ctx, g, c); err != nil {
+			return err
+		}
+
+		return l.populateTo(ctx, g)
+	}
+
 	if err := l.populateFrom(ctx, g); err != nil {
 		return err
 	}
@@ -160,7 +400,14 @@ func (l *Link) populateFrom(ctx context.Context, g github.Getter) error {
 		l.From.Ref = l.From.Repo.DefaultBranch
 	}
 
-	if err := g.GetFile(ctx, &l.From); err != nil {
+	// NOTE: The content is only needed when it's modified, otherwise its
+	// SHA is enough until it's written.
+	get := g.GetFile
+	if l.verbatim() {
+		get = g.GetFileInfo
+	}
+
+	if err := get(ctx, &l.From); err != nil {
 		return fmt.Errorf("%w %#v: %w", errMissingFrom, l.From, err)
 	}
 
@@ -168,12 +415,12 @@ func (l *Link) populateFrom(ctx context.Context, g github.Getter) error {
 }
 
 func (l *Link) populateTo(ctx context.Context, g github.Getter) error {
-	refs := []string{"auto-action-ln", l.To.Ref}
+	refs := []string{l.Branch, l.To.Ref}
 
 	for _, ref := range refs {
 		l.To.Ref = ref
 
-		err := g.GetFile(ctx, &l.To)
+		err := g.GetFileInfo(ctx, &l.To)
 		if err == nil {
 			return nil
 		}
@@ -201,6 +448,17 @@ func (l *Link) fillMissing() {
 }
 
 func (l *Link) fillDefaults(d Defaults) {
+	if l.Branch == "" {
+		l.Branch = d.Branch
+	}
+
+	if l.Rebase == nil {
+		l.Rebase = d.Rebase
+	}
+
+	l.Pull.fillDefaults(d.Pull)
+	l.Commit.fillDefaults(d.Commit)
+
 	if d.Link == nil {
 		return
 	}
@@ -220,21 +478,51 @@ func (l *Link) fillDefaults(d Defaults) {
 	if l.To.Path == "" {
 		l.To.Path = d.Link.To.Path
 	}
+
+	if l.Delete == nil {
+		l.Delete = d.Link.Delete
+	}
+
+	if l.Template == nil {
+		l.Template = d.Link.Template
+	}
+
+	l.Vars = mergeVars(d.Link.Vars, l.Vars)
+
+	if l.Transforms == nil {
+		l.Transforms = d.Link.Transforms
+	}
+
+	if l.Section == nil {
+		l.Section = d.Link.Section
+	}
+
+	if l.Merge == nil {
+		l.Merge = d.Link.Merge
+	}
+
+	if l.Concat == nil {
+		l.Concat = d.Link.Concat
+	}
+}
+
+type templateField struct {
+	name  string
+	value *string
+}
+
+// applyFromTemplate templates the repo and ref of `from`, which are needed
+// to resolve the ref and expand the globs before the rest is templated.
+func (l *Link) applyFromTemplate(c *Config) error {
+	return l.applyTemplateFields(c, []templateField{
+		{name: "From.Ref", value: &l.From.Ref},
+		{name: "From.Repo.Owner.Login", value: &l.From.Repo.Owner.Login},
+		{name: "From.Repo.Repo", value: &l.From.Repo.Repo},
+	})
 }
 
 func (l *Link) applyTemplate(c *Config) error {
-	data := struct {
-		Config *Config
-		Link   *Link
-	}{
-		Config: c,
-		Link:   l,
-	}
-
-	fields := []struct {
-		name  string
-		value *string
-	}{
+	return l.applyTemplateFields(c, []templateField{
 		{name: "From.Name", value: &l.From.Name},
 		{name: "From.Path", value: &l.From.Path},
 		{name: "From.Ref", value: &l.From.Ref},
@@ -246,7 +534,11 @@ func (l *Link) applyTemplate(c *Config) error {
 		{name: "To.Ref", value: &l.To.Ref},
 		{name: "To.Repo.Owner.Login", value: &l.To.Repo.Owner.Login},
 		{name: "To.Repo.Repo", value: &l.To.Repo.Repo},
-	}
+	})
+}
+
+func (l *Link) applyTemplateFields(c *Config, fields []templateField) error {
+	data := l.templateData(c)
 
 	for _, f := range fields {
 		if err := template.Update(f.value, data); err != nil {
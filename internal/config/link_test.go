@@ -207,7 +207,7 @@ func TestPopulate(t *testing.T) {
 
 		l := &Link{}
 
-		if err := l.populate(t.Context(), f); !errors.Is(err, errGettingRepo) {
+		if err := l.populate(t.Context(), f, &Config{}); !errors.Is(err, errGettingRepo) {
 			t.Fatalf("expected error %v, got %v", errGettingRepo, err)
 		}
 	})
@@ -229,7 +229,7 @@ func TestPopulate(t *testing.T) {
 			From: github.File{Path: "from", Ref: "main"},
 		}
 
-		if err := l.populate(t.Context(), f); !errors.Is(err, errMissingTo) {
+		if err := l.populate(t.Context(), f, &Config{}); !errors.Is(err, errMissingTo) {
 			t.Fatalf("expected error %v, got %v", errMissingTo, err)
 		}
 	})
@@ -254,7 +254,7 @@ func TestPopulate(t *testing.T) {
 			To:   github.File{Path: "to"},
 		}
 
-		if err := l.populate(t.Context(), f); err != nil {
+		if err := l.populate(t.Context(), f, &Config{}); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
@@ -279,7 +279,7 @@ func TestPopulate(t *testing.T) {
 			To:   github.File{Path: "to"},
 		}
 
-		if err := l.populate(t.Context(), f); err != nil {
+		if err := l.populate(t.Context(), f, &Config{}); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
@@ -394,6 +394,65 @@ func TestPopulateFrom(t *testing.T) {
 			t.Fatalf("expected ref to stay to 'main', got %#v", l.From.Ref)
 		}
 	})
+
+	t.Run("only gets the info of a verbatim file", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			FileHandler: func(*github.File) error {
+				t.Fatalf("FileHandler should not be called in this test")
+
+				return nil
+			},
+			InfoHandler: func(f *github.File) error {
+				f.SHA = "sha"
+
+				return nil
+			},
+		}
+
+		l := &Link{
+			From: github.File{Ref: "main"},
+		}
+
+		if err := l.populateFrom(t.Context(), f); err != nil {
+			t.Fatalf("expected no error got %v", err)
+		}
+
+		if l.From.SHA != "sha" || l.From.Content != "" {
+			t.Fatalf("expected only the info, got %#v", l.From)
+		}
+	})
+
+	t.Run("gets the content of a modified file", func(t *testing.T) {
+		t.Parallel()
+
+		f := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				f.Content = content
+
+				return nil
+			},
+			InfoHandler: func(*github.File) error {
+				t.Fatalf("InfoHandler should not be called in this test")
+
+				return nil
+			},
+		}
+
+		l := &Link{
+			From:       github.File{Ref: "main"},
+			Transforms: []Transform{{Append: "a"}},
+		}
+
+		if err := l.populateFrom(t.Context(), f); err != nil {
+			t.Fatalf("expected no error got %v", err)
+		}
+
+		if l.From.Content != content {
+			t.Fatalf("expected content to be 'content', got %#v", l.From.Content)
+		}
+	})
 }
 
 func TestPopulateTo(t *testing.T) {
@@ -508,29 +567,52 @@ func TestPopulateTo(t *testing.T) {
 	})
 }
 
-func TestLinkUpdate(t *testing.T) {
+func TestLinkTreeEntry(t *testing.T) {
 	t.Parallel()
 
-	head := github.Branch{Name: "head"}
+	t.Run("fail to create the blob", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.GetterUpdater{Updater: gmock.Updater{
+			BlobHandler: func(github.Repo, string) (string, error) { return "", errTest },
+		}}
 
-	t.Run("fail to update", func(t *testing.T) {
+		l := &Link{
+			To:   github.File{Content: "to"},
+			From: github.File{Content: "from"},
+		}
+
+		if _, err := l.treeEntry(t.Context(), g, ""); !errors.Is(err, errTest) {
+			t.Fatalf("want error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.Updater{
-			Handler: func(_ github.File, _ string, _ string) (github.File, error) {
-				return github.File{}, errTest
+		g := gmock.GetterUpdater{Updater: gmock.Updater{
+			BlobHandler: func(_ github.Repo, c string) (string, error) {
+				if c != "from" {
+					t.Fatalf("want content 'from', got %q", c)
+				}
+
+				return "blob", nil
 			},
-		}
+		}}
 
 		l := &Link{
-			To:   github.File{Content: "to"},
+			To:   github.File{Path: "to", Content: "to"},
 			From: github.File{Content: "from"},
 		}
 
-		err := l.Update(t.Context(), g, fmock.New(), head)
+		got, err := l.treeEntry(t.Context(), g, "")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
 
-		if !errors.Is(err, errTest) {
-			t.Fatalf("want error %v, got %v", errTest, err)
+		want := github.TreeEntry{Path: "to", Mode: github.ModeFile, Type: github.TypeBlob, SHA: "blob"}
+		if got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
 		}
 
 		if l.To.Content != l.From.Content {
@@ -538,27 +620,84 @@ func TestLinkUpdate(t *testing.T) {
 		}
 	})
 
-	t.Run("update", func(t *testing.T) {
+	t.Run("keeps the mode", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.Updater{
-			Handler: func(_ github.File, _ string, _ string) (github.File, error) {
-				// NOTE: the returned file is currently not used.
-				return github.File{}, nil
-			},
+		g := gmock.GetterUpdater{Updater: gmock.Updater{
+			BlobHandler: func(github.Repo, string) (string, error) { return "blob", nil },
+		}}
+
+		l := &Link{}
+
+		got, err := l.treeEntry(t.Context(), g, "100755")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
 		}
 
+		if got.Mode != "100755" {
+			t.Fatalf("want mode '100755', got %q", got.Mode)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
 		l := &Link{
-			To:   github.File{Content: "to"},
-			From: github.File{Content: "from"},
+			To:     github.File{Path: "to"},
+			Orphan: true,
 		}
 
-		if err := l.Update(t.Context(), g, fmock.New(), head); err != nil {
+		got, err := l.treeEntry(t.Context(), gmock.GetterUpdater{}, "")
+		if err != nil {
 			t.Fatalf("want no error, got %v", err)
 		}
 
-		if l.To.Content != l.From.Content {
-			t.Fatal("want link content to be updated but isn't")
+		if got.SHA != "" || got.Path != "to" {
+			t.Fatalf("want a deletion of 'to', got %+v", got)
+		}
+	})
+	t.Run("loads the content", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.GetterUpdater{
+			Getter: gmock.Getter{
+				FileHandler: func(f *github.File) error {
+					f.Content = "from"
+
+					return nil
+				},
+			},
+			Updater: gmock.Updater{
+				BlobHandler: func(_ github.Repo, c string) (string, error) {
+					if c != "from" {
+						t.Fatalf("want content 'from', got %q", c)
+					}
+
+					return "blob", nil
+				},
+			},
+		}
+
+		l := &Link{From: github.File{SHA: "sha"}}
+
+		if _, err := l.treeEntry(t.Context(), g, ""); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("fail to load the content", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.GetterUpdater{
+			Getter: gmock.Getter{
+				FileHandler: func(*github.File) error { return errTest },
+			},
+		}
+
+		l := &Link{From: github.File{SHA: "sha"}}
+
+		if _, err := l.treeEntry(t.Context(), g, ""); !errors.Is(err, errMissingFrom) {
+			t.Fatalf("want error %v, got %v", errMissingFrom, err)
 		}
 	})
 }
@@ -628,7 +767,7 @@ func TestParseLink(t *testing.T) {
 
 			c := New(github.File{}, github.Repo{})
 
-			got, err := c.parseLink(test.rl)
+			got, err := c.parseLink(t.Context(), gmock.Getter{}, test.rl)
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
 			}
@@ -640,6 +779,162 @@ func TestParseLink(t *testing.T) {
 	}
 }
 
+func TestParseLinkOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uses the defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		got, err := c.parseLink(t.Context(), gmock.Getter{}, RawLink{From: "from", To: "to"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got[0].Branch != defaultBranch {
+			t.Fatalf("expected branch %q, got %q", defaultBranch, got[0].Branch)
+		}
+
+		if got[0].Pull.Title != defaultPullTitle {
+			t.Fatalf("expected pull title %q, got %q", defaultPullTitle, got[0].Pull.Title)
+		}
+
+		if got[0].ShouldRebase() {
+			t.Fatal("expected no rebase")
+		}
+	})
+
+	t.Run("overrides the defaults", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+		yes := true
+
+		got, err := c.parseLink(t.Context(), gmock.Getter{}, RawLink{
+			From:   "from",
+			To:     "to",
+			Branch: "branch",
+			Rebase: &yes,
+			Pull:   Pull{Title: "title"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got[0].Branch != "branch" {
+			t.Fatalf("expected branch %q, got %q", "branch", got[0].Branch)
+		}
+
+		if got[0].Pull.Title != "title" {
+			t.Fatalf("expected pull title %q, got %q", "title", got[0].Pull.Title)
+		}
+
+		if !got[0].ShouldRebase() {
+			t.Fatal("expected rebase")
+		}
+	})
+
+	t.Run("fails on an invalid template", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		_, err := c.parseLink(t.Context(), gmock.Getter{}, RawLink{
+			From:   "from",
+			To:     "to",
+			Commit: Commit{Message: "{{ end }}"},
+		})
+		if !errors.Is(err, errInvalidTemplate) {
+			t.Fatalf("expected error %v, got %v", errInvalidTemplate, err)
+		}
+	})
+
+	t.Run("templates the from repo before expanding the glob", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+		g := gmock.Getter{
+			TreeHandler: func(r github.Repo, _ string) (github.Tree, error) {
+				if r.String() != "owner/repo" {
+					t.Fatalf("expected repo %q, got %q", "owner/repo", r)
+				}
+
+				return github.Tree{Entries: []github.TreeEntry{{Path: "a.txt", Type: github.TypeBlob}}}, nil
+			},
+		}
+
+		got, err := c.parseLink(t.Context(), g, RawLink{
+			From: map[string]any{"repo": "owner/{{ .Vars.repo }}", "path": "*.txt"},
+			To:   map[string]any{"repo": "owner/other"},
+			Vars: map[string]any{"repo": "repo"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0].From.Path != "a.txt" {
+			t.Fatalf("expected a link from %q, got %+v", "a.txt", got)
+		}
+	})
+
+	t.Run("templates the from repo before resolving the ref", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+		g := gmock.Getter{
+			TagsHandler: func(r github.Repo) ([]github.Tag, error) {
+				if r.String() != "owner/repo" {
+					t.Fatalf("expected repo %q, got %q", "owner/repo", r)
+				}
+
+				return []github.Tag{{Name: "v1.0.0"}, {Name: "v1.2.0"}, {Name: "v2.0.0"}}, nil
+			},
+		}
+
+		got, err := c.parseLink(t.Context(), g, RawLink{
+			From: map[string]any{"repo": "owner/{{ .Vars.repo }}", "path": "file", "ref": "{{ .Vars.ref }}"},
+			Vars: map[string]any{"repo": "repo", "ref": "^1"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got[0].From.Ref != "v1.2.0" {
+			t.Fatalf("expected ref %q, got %q", "v1.2.0", got[0].From.Ref)
+		}
+	})
+
+	t.Run("accepts the functions of the concat header", func(t *testing.T) {
+		t.Parallel()
+
+		c := New(github.File{}, github.Repo{})
+
+		_, err := c.parseLink(t.Context(), gmock.Getter{}, RawLink{
+			From:   "from",
+			To:     "to",
+			Concat: &Concat{Header: "{{ pathTrimN .From.Path 1 }}"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestLinkFormat(t *testing.T) {
+	t.Parallel()
+
+	l := &Link{Branch: "branch"}
+
+	if err := l.Format(fmock.New()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if l.Branch != "branch" {
+		t.Fatalf("expected branch %q, got %q", "branch", l.Branch)
+	}
+}
+
 func TestFillMissing(t *testing.T) {
 	t.Parallel()
 
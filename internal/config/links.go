@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/nobe4/action-ln/internal/format"
@@ -9,6 +10,8 @@ import (
 	"github.com/nobe4/action-ln/internal/log"
 )
 
+var errConflictingGroup = errors.New("conflicting links in group")
+
 type Links []*Link
 
 func (l *Links) Equal(other []*Link) bool {
@@ -25,11 +28,11 @@ func (l *Links) Equal(other []*Link) bool {
 	return true
 }
 
-func (c *Config) parseLinks(raw []RawLink) (Links, error) {
+func (c *Config) parseLinks(ctx context.Context, g github.Getter, raw []RawLink) (Links, error) {
 	links := Links{}
 
 	for i, rl := range raw {
-		l, err := c.parseLink(rl)
+		l, err := c.parseLink(ctx, g, rl)
 		if err != nil {
 			log.Debug("Failed to parse link", "index", i, "raw", rl, "error")
 
@@ -42,7 +45,7 @@ func (c *Config) parseLinks(raw []RawLink) (Links, error) {
 	return links, nil
 }
 
-func (c *Config) parseLink(raw RawLink) (Links, error) {
+func (c *Config) parseLink(ctx context.Context, g github.Getter, raw RawLink) (Links, error) {
 	log.Group(fmt.Sprintf("Parse link: %+v", raw))
 	defer log.GroupEnd()
 
@@ -58,14 +61,35 @@ func (c *Config) parseLink(raw RawLink) (Links, error) {
 
 	links := combineLinks(froms, tos)
 
+	for _, l := range links {
+		raw.applyOptions(l)
+	}
+
 	links.FillDefaults(c.Defaults)
 	links.FillMissing()
 
+	if err := links.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := links.ApplyFromTemplate(c); err != nil {
+		return nil, err
+	}
+
+	if err := links.ResolveRefs(ctx, g, c); err != nil {
+		return nil, err
+	}
+
+	if err := links.ExpandGlobs(ctx, g, c); err != nil {
+		return nil, err
+	}
+
 	if err := links.ApplyTemplate(c); err != nil {
 		return nil, err
 	}
 
 	links.Filter()
+	links.Concat()
 
 	return links, nil
 }
@@ -107,6 +131,70 @@ func (l *Links) FillDefaults(d Defaults) {
 	}
 }
 
+func (l *Links) ExpandGlobs(ctx context.Context, g github.Getter, c *Config) error {
+	newL := Links{}
+
+	for _, l := range *l {
+		expanded, err := l.expandGlob(ctx, g, c)
+		if err != nil {
+			return err
+		}
+
+		newL = append(newL, expanded...)
+	}
+
+	*l = newL
+
+	return nil
+}
+
+func (l *Links) ExpandDirectories(ctx context.Context, g github.Getter, c *Config) error {
+	newL := Links{}
+
+	for _, l := range *l {
+		expanded, err := l.expandDirectory(ctx, g, c)
+		if err != nil {
+			return err
+		}
+
+		newL = append(newL, expanded...)
+	}
+
+	*l = newL
+
+	return nil
+}
+
+func (l *Links) Validate() error {
+	for _, l := range *l {
+		if err := l.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Links) Format(f format.Formatter) error {
+	for _, l := range *l {
+		if err := l.Format(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Links) ApplyFromTemplate(c *Config) error {
+	for _, l := range *l {
+		if err := l.applyFromTemplate(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (l *Links) ApplyTemplate(c *Config) error {
 	for _, l := range *l {
 		if err := l.applyTemplate(c); err != nil {
@@ -133,13 +221,15 @@ func (l *Links) Filter() {
 	*l = newL
 }
 
+// Update writes the links that need it in a single commit on the head branch.
+// If any of them fails, none is written.
 func (l *Links) Update(
 	ctx context.Context,
 	g github.GetterUpdater,
 	f format.Formatter,
 	head github.Branch,
 ) bool {
-	updated := false
+	toUpdate := Links{}
 
 	for _, link := range *l {
 		needUpdate, err := link.NeedUpdate(ctx, g, head)
@@ -157,32 +247,80 @@ func (l *Links) Update(
 			continue
 		}
 
-		if err := link.Update(ctx, g, f, head); err != nil {
-			log.Error("failed to update", "link", link, "error", err)
-			link.Status = StatusFailedToUpdate
+		toUpdate = append(toUpdate, link)
+	}
 
-			continue
+	if len(toUpdate) == 0 {
+		return false
+	}
+
+	toUpdate.History(ctx, g)
+
+	if err := toUpdate.commit(ctx, g, f, head); err != nil {
+		log.Error("failed to update", "links", toUpdate, "error", err)
+
+		for _, link := range toUpdate {
+			link.Status = StatusFailedToUpdate
 		}
 
-		updated = true
+		return false
+	}
+
+	for _, link := range toUpdate {
 		link.Status = StatusUpdated
+
+		if link.Orphan {
+			link.Status = StatusDeleted
+		}
 	}
 
-	return updated
+	return true
 }
 
+// Groups are the links sharing the same `to` repository and head branch.
+// They are updated in the same pull request.
 type Groups map[string]Links
 
 func (l *Links) Groups() Groups {
 	g := make(Groups)
 
 	for _, link := range *l {
-		g[link.To.Repo.String()] = append(g[link.To.Repo.String()], link)
+		k := link.To.Repo.String() + "@" + link.Branch
+		g[k] = append(g[k], link)
 	}
 
 	return g
 }
 
+// Validate checks that the links of each group agree on the settings of the
+// pull request, commit, and branch they share.
+func (g Groups) Validate() error {
+	for n, l := range g {
+		for _, link := range l[1:] {
+			if field := link.groupConflict(l[0]); field != "" {
+				return fmt.Errorf("%w %q: %s differs between %s and %s", errConflictingGroup, n, field, l[0], link)
+			}
+		}
+	}
+
+	return nil
+}
+
+// groupConflict returns the first setting shared by the group that differs
+// from o, or "".
+func (l *Link) groupConflict(o *Link) string {
+	switch {
+	case !l.Pull.equal(o.Pull):
+		return "pull"
+	case l.Commit != o.Commit:
+		return "commit"
+	case l.ShouldRebase() != o.ShouldRebase():
+		return "rebase"
+	default:
+		return ""
+	}
+}
+
 func (g Groups) String() string {
 	out := ""
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"testing"
 
 	fmock "github.com/nobe4/action-ln/internal/format/mock"
@@ -174,15 +175,52 @@ func TestFilter(t *testing.T) {
 func TestLinksUpdate(t *testing.T) {
 	t.Parallel()
 
-	head := github.Branch{New: false}
+	head := github.Branch{Name: "head", Commit: github.Commit{SHA: "head_sha"}}
 
 	const got = "got"
 
+	// mkGetterUpdater returns a GetterUpdater where every call succeeds.
+	mkGetterUpdater := func() gmock.GetterUpdater {
+		return gmock.GetterUpdater{
+			Getter: gmock.Getter{
+				FileHandler: func(f *github.File) error {
+					f.Content = got
+					f.SHA = "sha"
+
+					return nil
+				},
+				TreeHandler: func(github.Repo, string) (github.Tree, error) {
+					return github.Tree{SHA: "head_tree"}, nil
+				},
+			},
+			Updater: gmock.Updater{
+				BlobHandler: func(_ github.Repo, c string) (string, error) {
+					if c == "error" {
+						return "", errTest
+					}
+
+					return "blob", nil
+				},
+				TreeHandler: func(github.Repo, string, []github.TreeEntry) (github.Tree, error) {
+					return github.Tree{SHA: "tree"}, nil
+				},
+				CommitHandler: func(github.Repo, string, string, []string) (github.Commit, error) {
+					return github.Commit{SHA: "commit"}, nil
+				},
+				BranchHandler: func(_ github.Repo, name, sha string) (github.Branch, error) {
+					return github.Branch{Name: name, Commit: github.Commit{SHA: sha}}, nil
+				},
+			},
+		}
+	}
+
 	t.Run("fail to check if the link needs an update", func(t *testing.T) {
 		t.Parallel()
 
 		g := gmock.GetterUpdater{
-			GetFileHandler: func(*github.File) error { return errTest },
+			Getter: gmock.Getter{
+				FileHandler: func(*github.File) error { return errTest },
+			},
 		}
 
 		l := &Links{
@@ -224,15 +262,9 @@ func TestLinksUpdate(t *testing.T) {
 	t.Run("fail to update the link", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.GetterUpdater{
-			GetFileHandler: func(f *github.File) error {
-				f.Content = got
-
-				return nil
-			},
-			UpdateHandler: func(github.File, string, string) (github.File, error) {
-				return github.File{}, errTest
-			},
+		g := mkGetterUpdater()
+		g.Updater.TreeHandler = func(github.Repo, string, []github.TreeEntry) (github.Tree, error) {
+			return github.Tree{}, errTest
 		}
 
 		l := &Links{
@@ -256,15 +288,28 @@ func TestLinksUpdate(t *testing.T) {
 	t.Run("update the link", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.GetterUpdater{
-			GetFileHandler: func(f *github.File) error {
-				f.Content = got
+		moved := false
 
-				return nil
-			},
-			UpdateHandler: func(github.File, string, string) (github.File, error) {
-				return github.File{}, nil
-			},
+		g := mkGetterUpdater()
+		g.Updater.CommitHandler = func(_ github.Repo, _, tree string, parents []string) (github.Commit, error) {
+			if tree != "tree" {
+				t.Fatalf("want tree 'tree', got %q", tree)
+			}
+
+			if len(parents) != 1 || parents[0] != head.Commit.SHA {
+				t.Fatalf("want parents [%s], got %v", head.Commit.SHA, parents)
+			}
+
+			return github.Commit{SHA: "commit"}, nil
+		}
+		g.BranchHandler = func(_ github.Repo, name, sha string) (github.Branch, error) {
+			if name != head.Name || sha != "commit" {
+				t.Fatalf("want %s to move to 'commit', got %s to %q", head.Name, name, sha)
+			}
+
+			moved = true
+
+			return github.Branch{}, nil
 		}
 
 		l := &Links{
@@ -279,26 +324,74 @@ func TestLinksUpdate(t *testing.T) {
 		if !updated {
 			t.Fatal("want to be updated")
 		}
+
+		if !moved {
+			t.Fatal("want the head branch to be moved")
+		}
 	})
 
-	t.Run("multiple links", func(t *testing.T) {
+	t.Run("records the synced commit", func(t *testing.T) {
 		t.Parallel()
 
-		g := gmock.GetterUpdater{
-			GetFileHandler: func(f *github.File) error {
-				f.Content = got
+		g := mkGetterUpdater()
+		g.CommitsHandler = func(github.Repo, string, string, int) ([]github.RepoCommit, error) {
+			return []github.RepoCommit{{SHA: "source_sha"}}, nil
+		}
+		g.Updater.CommitHandler = func(_ github.Repo, msg, _ string, _ []string) (github.Commit, error) {
+			want := "message\n\nSynced-From: o/r:from@source_sha\n"
+			if msg != want {
+				t.Fatalf("want message %q, got %q", want, msg)
+			}
+
+			return github.Commit{SHA: "commit"}, nil
+		}
 
-				return nil
+		l := &Links{
+			{
+				From:   github.File{Repo: github.Repo{Owner: github.User{Login: "o"}, Repo: "r"}, Path: "from", Content: "from"},
+				To:     github.File{Content: "to"},
+				Commit: Commit{Message: "message\n"},
 			},
-			UpdateHandler: func(f github.File, _ string, _ string) (github.File, error) {
-				if f.Content == "error" {
-					return github.File{}, errTest
-				}
+		}
 
-				return github.File{}, nil
+		if !l.Update(t.Context(), g, fmock.New(), head) {
+			t.Fatal("want to be updated")
+		}
+	})
+
+	t.Run("delete the link", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Links{
+			{
+				To:     github.File{SHA: "sha"},
+				Orphan: true,
 			},
 		}
 
+		updated := l.Update(t.Context(), mkGetterUpdater(), fmock.New(), head)
+
+		if s := (*l)[0].Status; s != "deleted" {
+			t.Fatalf("want status 'deleted', got '%s'", s)
+		}
+
+		if !updated {
+			t.Fatal("want to be updated")
+		}
+	})
+
+	t.Run("multiple links", func(t *testing.T) {
+		t.Parallel()
+
+		entries := []github.TreeEntry{}
+
+		g := mkGetterUpdater()
+		g.Updater.TreeHandler = func(_ github.Repo, _ string, e []github.TreeEntry) (github.Tree, error) {
+			entries = e
+
+			return github.Tree{SHA: "tree"}, nil
+		}
+
 		l := &Links{
 			// Needs no update
 			{
@@ -312,10 +405,10 @@ func TestLinksUpdate(t *testing.T) {
 				To:   github.File{Content: "to"},
 			},
 
-			// Fails to update
+			// Deletes correctly
 			{
-				From: github.File{Content: "error"},
-				To:   github.File{Content: "to"},
+				To:     github.File{SHA: "sha"},
+				Orphan: true,
 			},
 		}
 
@@ -329,15 +422,55 @@ func TestLinksUpdate(t *testing.T) {
 			t.Fatalf("want status 'updated', got '%s'", s)
 		}
 
-		if s := (*l)[2].Status; s != "failed to update" {
-			t.Fatalf("want status 'failed to update', got '%s'", s)
+		if s := (*l)[2].Status; s != "deleted" {
+			t.Fatalf("want status 'deleted', got '%s'", s)
+		}
+
+		if len(entries) != 2 {
+			t.Fatalf("want the 2 links in a single tree, got %v", entries)
 		}
 
-		// The function failed but we had a valid update.
 		if !updated {
 			t.Fatal("want to be updated")
 		}
 	})
+
+	t.Run("one failure fails all the links", func(t *testing.T) {
+		t.Parallel()
+
+		g := mkGetterUpdater()
+		g.BranchHandler = func(github.Repo, string, string) (github.Branch, error) {
+			t.Fatal("BranchHandler should not be called in this test")
+
+			return github.Branch{}, nil
+		}
+
+		l := &Links{
+			// Updates correctly
+			{
+				From: github.File{Content: "from"},
+				To:   github.File{Content: "to"},
+			},
+
+			// Fails to update
+			{
+				From: github.File{Content: "error"},
+				To:   github.File{Content: "to"},
+			},
+		}
+
+		updated := l.Update(t.Context(), g, fmock.New(), head)
+
+		for i, link := range *l {
+			if link.Status != "failed to update" {
+				t.Fatalf("want link %d status 'failed to update', got '%s'", i, link.Status)
+			}
+		}
+
+		if updated {
+			t.Fatal("want to not be updated")
+		}
+	})
 }
 
 func TestGroups(t *testing.T) {
@@ -348,42 +481,124 @@ func TestGroups(t *testing.T) {
 			To: github.File{
 				Repo: github.Repo{Owner: github.User{Login: "a"}, Repo: "b"},
 			},
+			Branch: "x",
 		},
 
 		&Link{
 			To: github.File{
 				Repo: github.Repo{Owner: github.User{Login: "a"}, Repo: "b"},
 			},
+			Branch: "x",
 		},
 
 		&Link{
 			To: github.File{
 				Repo: github.Repo{Owner: github.User{Login: "a"}, Repo: "c"},
 			},
+			Branch: "x",
 		},
 
 		&Link{
 			To: github.File{
 				Repo: github.Repo{Owner: github.User{Login: "d"}, Repo: "e"},
 			},
+			Branch: "x",
+		},
+
+		&Link{
+			To: github.File{
+				Repo: github.Repo{Owner: github.User{Login: "a"}, Repo: "b"},
+			},
+			Branch: "y",
 		},
 	}
 
 	got := links.Groups()
 
-	if got["a/b"][0] != links[0] {
-		t.Fatalf("expected %v, got %v", links[0], got["a/b"][0])
+	if got["a/b@x"][0] != links[0] {
+		t.Fatalf("expected %v, got %v", links[0], got["a/b@x"][0])
 	}
 
-	if got["a/b"][1] != links[1] {
-		t.Fatalf("expected %v, got %v", links[1], got["a/b"][1])
+	if got["a/b@x"][1] != links[1] {
+		t.Fatalf("expected %v, got %v", links[1], got["a/b@x"][1])
 	}
 
-	if got["a/c"][0] != links[2] {
-		t.Fatalf("expected %v, got %v", links[2], got["a/c"][0])
+	if got["a/c@x"][0] != links[2] {
+		t.Fatalf("expected %v, got %v", links[2], got["a/c@x"][0])
+	}
+
+	if got["d/e@x"][0] != links[3] {
+		t.Fatalf("expected %v, got %v", links[3], got["d/e@x"][0])
+	}
+
+	if got["a/b@y"][0] != links[4] {
+		t.Fatalf("expected %v, got %v", links[4], got["a/b@y"][0])
+	}
+}
+
+func TestGroupsValidate(t *testing.T) {
+	t.Parallel()
+
+	repo := github.Repo{Owner: github.User{Login: "a"}, Repo: "b"}
+	yes := true
+
+	mkGroup := func(edit func(*Link)) Groups {
+		other := &Link{
+			To:     github.File{Repo: repo, Path: "y"},
+			Branch: "x",
+			Pull:   Pull{Title: "title", Labels: []string{"l"}},
+			Commit: Commit{Message: "message"},
+		}
+		edit(other)
+
+		links := Links{
+			&Link{
+				To:     github.File{Repo: repo, Path: "x"},
+				Branch: "x",
+				Pull:   Pull{Title: "title", Labels: []string{"l"}},
+				Commit: Commit{Message: "message"},
+			},
+			other,
+		}
+
+		return links.Groups()
 	}
 
-	if got["d/e"][0] != links[3] {
-		t.Fatalf("expected %v, got %v", links[3], got["d/e"][0])
+	t.Run("accepts the same settings", func(t *testing.T) {
+		t.Parallel()
+
+		if err := mkGroup(func(*Link) {}).Validate(); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("accepts different settings in different groups", func(t *testing.T) {
+		t.Parallel()
+
+		g := mkGroup(func(l *Link) {
+			l.Branch = "y"
+			l.Pull.Title = "other"
+		})
+
+		if err := g.Validate(); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	})
+
+	for name, edit := range map[string]func(*Link){
+		"title":  func(l *Link) { l.Pull.Title = "other" },
+		"labels": func(l *Link) { l.Pull.Labels = []string{"other"} },
+		"draft":  func(l *Link) { l.Pull.Draft = &yes },
+		"merge":  func(l *Link) { l.Pull.Merge.Mode = MergeAuto },
+		"commit": func(l *Link) { l.Commit.Message = "other" },
+		"rebase": func(l *Link) { l.Rebase = &yes },
+	} {
+		t.Run("rejects a different "+name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := mkGroup(edit).Validate(); !errors.Is(err, errConflictingGroup) {
+				t.Fatalf("want %v, got %v", errConflictingGroup, err)
+			}
+		})
 	}
 }
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	MergeYAML = "yaml"
+	MergeJSON = "json"
+
+	// MergeListsReplace replaces the destination list with the source one.
+	MergeListsReplace = "replace"
+	// MergeListsAppend appends the source items missing from the destination.
+	MergeListsAppend = "append"
+	// MergeListsUnique is the union of both lists, without duplicates.
+	MergeListsUnique = "unique"
+)
+
+var (
+	errInvalidMerge = errors.New("invalid merge")
+	errMerge        = errors.New("failed to merge")
+
+	mergeFormats    = []string{MergeYAML, MergeJSON}
+	mergeListsModes = []string{MergeListsReplace, MergeListsAppend, MergeListsUnique}
+)
+
+// Merge deep-merges the `from` document into the existing `to` one, instead of
+// overwriting it.
+type Merge struct {
+	// Format is one of yaml or json.
+	Format string `json:"format" yaml:"format"`
+
+	// Lists is one of replace, append, or unique. It defaults to replace.
+	Lists string `json:"lists,omitempty" yaml:"lists,omitempty"`
+
+	// Pinned are the dot-separated paths of the keys the destination keeps.
+	// E.g. `run.timeout`.
+	Pinned []string `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+}
+
+// UnmarshalYAML allows the short form `merge: yaml`.
+func (m *Merge) UnmarshalYAML(unmarshal func(any) error) error {
+	format := ""
+	if err := unmarshal(&format); err == nil {
+		m.Format = format
+
+		return nil
+	}
+
+	type raw Merge
+
+	return unmarshal((*raw)(m))
+}
+
+func (m *Merge) validate() error {
+	if !slices.Contains(mergeFormats, m.Format) {
+		return fmt.Errorf("%w: format %q, want one of %v", errInvalidMerge, m.Format, mergeFormats)
+	}
+
+	if m.Lists != "" && !slices.Contains(mergeListsModes, m.Lists) {
+		return fmt.Errorf("%w: lists %q, want one of %v", errInvalidMerge, m.Lists, mergeListsModes)
+	}
+
+	return nil
+}
+
+// merge returns the `to` document with the `from` one merged into it.
+// YAML comments in `to` are kept.
+func (m *Merge) merge(to, from string) (string, error) {
+	if strings.TrimSpace(to) == "" {
+		return from, nil
+	}
+
+	comments := yaml.CommentMap{}
+
+	var dst, src any
+
+	if err := yaml.UnmarshalWithOptions([]byte(to), &dst, yaml.UseOrderedMap(), yaml.CommentToMap(comments)); err != nil {
+		return "", fmt.Errorf("%w: invalid `to`: %w", errMerge, err)
+	}
+
+	if err := yaml.UnmarshalWithOptions([]byte(from), &src, yaml.UseOrderedMap()); err != nil {
+		return "", fmt.Errorf("%w: invalid `from`: %w", errMerge, err)
+	}
+
+	merged := m.mergeValues("", dst, src)
+
+	if m.Format == MergeJSON {
+		return marshalJSON(merged)
+	}
+
+	out, err := yaml.MarshalWithOptions(merged, yaml.IndentSequence(true), yaml.WithComment(comments))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errMerge, err)
+	}
+
+	return string(out), nil
+}
+
+func (m *Merge) mergeValues(path string, dst, src any) any {
+	if path != "" && slices.Contains(m.Pinned, path) {
+		return dst
+	}
+
+	switch s := src.(type) {
+	case yaml.MapSlice:
+		if d, ok := dst.(yaml.MapSlice); ok {
+			return m.mergeMaps(path, d, s)
+		}
+
+	case []any:
+		if d, ok := dst.([]any); ok {
+			return m.mergeLists(d, s)
+		}
+	}
+
+	return src
+}
+
+func (m *Merge) mergeMaps(path string, dst, src yaml.MapSlice) yaml.MapSlice {
+	merged := slices.Clone(dst)
+
+	for _, item := range src {
+		key := strings.TrimPrefix(fmt.Sprintf("%s.%v", path, item.Key), ".")
+
+		i := slices.IndexFunc(merged, func(d yaml.MapItem) bool { return d.Key == item.Key })
+		if i < 0 {
+			merged = append(merged, item)
+
+			continue
+		}
+
+		merged[i].Value = m.mergeValues(key, merged[i].Value, item.Value)
+	}
+
+	return merged
+}
+
+func (m *Merge) mergeLists(dst, src []any) []any {
+	contains := func(l []any, v any) bool {
+		return slices.ContainsFunc(l, func(i any) bool { return reflect.DeepEqual(i, v) })
+	}
+
+	switch m.Lists {
+	case MergeListsAppend:
+		merged := slices.Clone(dst)
+
+		for _, v := range src {
+			if !contains(dst, v) {
+				merged = append(merged, v)
+			}
+		}
+
+		return merged
+
+	case MergeListsUnique:
+		merged := []any{}
+
+		for _, v := range append(slices.Clone(dst), src...) {
+			if !contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+
+		return merged
+
+	default:
+		return src
+	}
+}
+
+// marshalJSON keeps the order of the keys, which encoding/json doesn't do for
+// yaml.MapSlice.
+func marshalJSON(v any) (string, error) {
+	out, err := yaml.MarshalWithOptions(v, yaml.JSON())
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errMerge, err)
+	}
+
+	buf := bytes.Buffer{}
+	if err := json.Indent(&buf, bytes.TrimSpace(out), "", "  "); err != nil {
+		return "", fmt.Errorf("%w: %w", errMerge, err)
+	}
+
+	buf.WriteString("\n")
+
+	return buf.String(), nil
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+func TestMergeUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("short form", func(t *testing.T) {
+		t.Parallel()
+
+		l := RawLink{}
+		if err := yaml.UnmarshalWithOptions([]byte("merge: json"), &l, yaml.Strict()); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.Merge.Format != MergeJSON {
+			t.Fatalf("want format json, got %+v", l.Merge)
+		}
+	})
+
+	t.Run("long form", func(t *testing.T) {
+		t.Parallel()
+
+		l := RawLink{}
+		in := "merge:\n  format: yaml\n  lists: unique\n  pinned: [a.b]\n"
+
+		if err := yaml.UnmarshalWithOptions([]byte(in), &l, yaml.Strict()); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.Merge.Format != MergeYAML || l.Merge.Lists != MergeListsUnique || len(l.Merge.Pinned) != 1 {
+			t.Fatalf("want yaml/unique/[a.b], got %+v", l.Merge)
+		}
+	})
+}
+
+func TestMergeValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		merge Merge
+		want  error
+	}{
+		{name: "yaml", merge: Merge{Format: MergeYAML}},
+		{name: "json with lists", merge: Merge{Format: MergeJSON, Lists: MergeListsAppend}},
+		{name: "missing format", want: errInvalidMerge},
+		{name: "invalid format", merge: Merge{Format: "toml"}, want: errInvalidMerge},
+		{name: "invalid lists", merge: Merge{Format: MergeYAML, Lists: "zip"}, want: errInvalidMerge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.merge.validate(); !errors.Is(err, test.want) {
+				t.Fatalf("want error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestMergeMerge(t *testing.T) {
+	t.Parallel()
+
+	to := `# Repository specific.
+run:
+  timeout: 10m # Slow CI.
+linters:
+  enable:
+    - a
+    - b
+local: true
+`
+
+	from := `run:
+  timeout: 5m
+  tests: true
+linters:
+  enable:
+    - b
+    - c
+`
+
+	tests := []struct {
+		name  string
+		merge Merge
+		to    string
+		want  string
+	}{
+		{
+			name:  "missing destination",
+			merge: Merge{Format: MergeYAML},
+			want:  from,
+		},
+		{
+			name:  "replaces the lists",
+			merge: Merge{Format: MergeYAML},
+			to:    to,
+			want: `# Repository specific.
+run:
+  timeout: 5m # Slow CI.
+  tests: true
+linters:
+  enable:
+    - b
+    - c
+local: true
+`,
+		},
+		{
+			name:  "appends to the lists and keeps the pinned keys",
+			merge: Merge{Format: MergeYAML, Lists: MergeListsAppend, Pinned: []string{"run.timeout"}},
+			to:    to,
+			want: `# Repository specific.
+run:
+  timeout: 10m # Slow CI.
+  tests: true
+linters:
+  enable:
+    - a
+    - b
+    - c
+local: true
+`,
+		},
+		{
+			name:  "merges json",
+			merge: Merge{Format: MergeJSON, Lists: MergeListsUnique},
+			to:    `{"name": "repo", "scripts": {"lint": "old"}, "files": ["a", "a"]}`,
+			want: `{
+  "name": "repo",
+  "scripts": {
+    "lint": "new",
+    "test": "go test"
+  },
+  "files": [
+    "a",
+    "b"
+  ]
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := from
+			if test.merge.Format == MergeJSON {
+				f = `{"scripts": {"lint": "new", "test": "go test"}, "files": ["b"]}`
+			}
+
+			got, err := test.merge.merge(test.to, f)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want\n%s\ngot\n%s", test.want, got)
+			}
+
+			// Merging again doesn't change anything.
+			if test.to != "" {
+				again, err := test.merge.merge(got, f)
+				if err != nil || again != got {
+					t.Fatalf("want a stable merge, got\n%s\n%v", again, err)
+				}
+			}
+		})
+	}
+
+	t.Run("fails on invalid content", func(t *testing.T) {
+		t.Parallel()
+
+		m := Merge{Format: MergeYAML}
+
+		if _, err := m.merge("a: [", from); !errors.Is(err, errMerge) {
+			t.Fatalf("want error %v, got %v", errMerge, err)
+		}
+	})
+}
+
+func TestLinkMerge(t *testing.T) {
+	t.Parallel()
+
+	l := &Link{
+		From:  github.File{Content: "a: 1\n"},
+		To:    github.File{Content: "a: 1\nb: 2\n"},
+		Merge: &Merge{Format: MergeYAML},
+	}
+
+	needUpdate, err := l.NeedUpdate(t.Context(), nil, github.Branch{})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if needUpdate {
+		t.Fatal("want no update")
+	}
+
+	l.Section = &Section{}
+	if err := l.validate(); !errors.Is(err, errInvalidMerge) || !strings.Contains(err.Error(), "section") {
+		t.Fatalf("want error %v, got %v", errInvalidMerge, err)
+	}
+}
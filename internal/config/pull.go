@@ -0,0 +1,122 @@
+package config
+
+import "slices"
+
+const (
+	defaultBranch    = "auto-action-ln"
+	defaultPullTitle = "auto(ln): update links"
+	defaultPullBody  = `
+{{/* This defines a backtick character to use in the markdown. */}}
+{{- $b := "` + "`" + `" -}}
+This automated PR updates the following files:
+
+| From | To  | Status |
+| ---  | --- | ---    |
+{{ range .Data -}}
+| {{ if gt (len .Sources) 1 -}}
+{{ range $i, $s := .Sources }}{{ if $i }}<br>{{ end }}[{{ $b }}{{ $s }}{{ $b }}]({{ $s.HTMLURL }}){{ end }}
+{{- else -}}
+[{{ $b }}{{ .From }}{{ $b }}]({{ .From.HTMLURL }})
+{{- with .RequestedRef }} (resolved from {{ $b }}{{ . }}{{ $b }}){{ end }}
+{{- end }} | {{ $b }}{{ .To.Path }}{{ $b }} | {{ .Status }} |
+{{ end }}
+{{- with .Data.Changes }}
+
+<details><summary>Upstream changes</summary>
+
+{{ range . -}}
+- [{{ $b }}{{ .ShortSHA }}{{ $b }}]({{ .HTMLURL }}) {{ .Title }}
+{{ end }}
+</details>
+{{- end }}
+
+---
+
+| Quick links | [execution]({{ .Environment.ExecURL }}) | [configuration]({{ .Environment.Server }}{{ .Config.Source.HTMLPath }}) | [action-ln](https://github.com/nobe4/action-ln) |
+| --- | --- | --- | --- |
+`
+)
+
+// Pull configures the pull request opened for a group of links.
+type Pull struct {
+	Title string `json:"title" yaml:"title"`
+	Body  string `json:"body"  yaml:"body"`
+
+	Labels        []string `json:"labels"         yaml:"labels"`
+	Assignees     []string `json:"assignees"      yaml:"assignees"`
+	Reviewers     []string `json:"reviewers"      yaml:"reviewers"`
+	TeamReviewers []string `json:"team_reviewers" yaml:"team_reviewers"`
+	Milestone     int      `json:"milestone"      yaml:"milestone"`
+
+	// Draft creates the pull request as a draft, or converts the existing one.
+	Draft *bool `json:"draft,omitempty" yaml:"draft,omitempty"`
+
+	Merge PullMerge `json:"merge" yaml:"merge"`
+}
+
+// fillDefaults sets the missing fields from d.
+func (p *Pull) fillDefaults(d Pull) {
+	if p.Title == "" {
+		p.Title = d.Title
+	}
+
+	if p.Body == "" {
+		p.Body = d.Body
+	}
+
+	if p.Labels == nil {
+		p.Labels = d.Labels
+	}
+
+	if p.Assignees == nil {
+		p.Assignees = d.Assignees
+	}
+
+	if p.Reviewers == nil {
+		p.Reviewers = d.Reviewers
+	}
+
+	if p.TeamReviewers == nil {
+		p.TeamReviewers = d.TeamReviewers
+	}
+
+	if p.Milestone == 0 {
+		p.Milestone = d.Milestone
+	}
+
+	if p.Draft == nil {
+		p.Draft = d.Draft
+	}
+
+	p.Merge.fillDefaults(d.Merge)
+}
+
+// IsDraft reports whether the pull request is a draft.
+func (p *Pull) IsDraft() bool {
+	return p.Draft != nil && *p.Draft
+}
+
+// equal reports whether o configures the same pull request.
+func (p *Pull) equal(o Pull) bool {
+	return p.Title == o.Title &&
+		p.Body == o.Body &&
+		slices.Equal(p.Labels, o.Labels) &&
+		slices.Equal(p.Assignees, o.Assignees) &&
+		slices.Equal(p.Reviewers, o.Reviewers) &&
+		slices.Equal(p.TeamReviewers, o.TeamReviewers) &&
+		p.Milestone == o.Milestone &&
+		p.IsDraft() == o.IsDraft() &&
+		p.Merge == o.Merge
+}
+
+func (p *Pull) validate() error {
+	if err := validateTextTemplate("pull.title", p.Title); err != nil {
+		return err
+	}
+
+	if err := validateTemplate("pull.body", p.Body); err != nil {
+		return err
+	}
+
+	return p.Merge.validate()
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// MergeNone leaves the pull request open.
+	MergeNone = "none"
+	// MergeImmediate merges the pull request as soon as it is up to date.
+	MergeImmediate = "immediate"
+	// MergeAuto enables GitHub's auto-merge, which merges once checks pass.
+	MergeAuto = "auto"
+
+	defaultMergeMethod = "merge"
+)
+
+var (
+	errInvalidMergeMode   = errors.New("invalid merge mode")
+	errInvalidMergeMethod = errors.New("invalid merge method")
+
+	mergeModes   = []string{MergeNone, MergeImmediate, MergeAuto}
+	mergeMethods = []string{"merge", "squash", "rebase"}
+)
+
+// PullMerge configures how the pull request is merged.
+type PullMerge struct {
+	// Mode is one of none, immediate, or auto. It defaults to none.
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Method is one of merge, squash, or rebase. It defaults to merge.
+	Method string `json:"method" yaml:"method"`
+}
+
+// fillDefaults sets the missing fields from d.
+func (m *PullMerge) fillDefaults(d PullMerge) {
+	if m.Mode == "" {
+		m.Mode = d.Mode
+	}
+
+	if m.Method == "" {
+		m.Method = d.Method
+	}
+}
+
+// MergeMethod returns the method to merge with.
+func (m PullMerge) MergeMethod() string {
+	if m.Method == "" {
+		return defaultMergeMethod
+	}
+
+	return m.Method
+}
+
+func (m PullMerge) validate() error {
+	if m.Mode != "" && !slices.Contains(mergeModes, m.Mode) {
+		return fmt.Errorf("%w %q, want one of %v", errInvalidMergeMode, m.Mode, mergeModes)
+	}
+
+	if m.Method != "" && !slices.Contains(mergeMethods, m.Method) {
+		return fmt.Errorf("%w %q, want one of %v", errInvalidMergeMethod, m.Method, mergeMethods)
+	}
+
+	return nil
+}
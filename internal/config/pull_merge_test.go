@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPullMergeFillDefaults(t *testing.T) {
+	t.Parallel()
+
+	m := PullMerge{Method: "rebase"}
+	m.fillDefaults(PullMerge{Mode: MergeAuto, Method: "squash"})
+
+	if m.Mode != MergeAuto || m.Method != "rebase" {
+		t.Fatalf("want auto/rebase, got %+v", m)
+	}
+}
+
+func TestPullMergeMethod(t *testing.T) {
+	t.Parallel()
+
+	if got := (PullMerge{}).MergeMethod(); got != defaultMergeMethod {
+		t.Fatalf("want %q, got %q", defaultMergeMethod, got)
+	}
+
+	if got := (PullMerge{Method: "squash"}).MergeMethod(); got != "squash" {
+		t.Fatalf("want 'squash', got %q", got)
+	}
+}
+
+func TestPullMergeValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		merge PullMerge
+		want  error
+	}{
+		{name: "empty"},
+		{name: "valid", merge: PullMerge{Mode: MergeAuto, Method: "squash"}},
+		{name: "invalid mode", merge: PullMerge{Mode: "later"}, want: errInvalidMergeMode},
+		{name: "invalid method", merge: PullMerge{Method: "octopus"}, want: errInvalidMergeMethod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.merge.validate(); !errors.Is(err, test.want) {
+				t.Fatalf("want error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPullFillDefaults(t *testing.T) {
+	t.Parallel()
+
+	yes, no := true, false
+
+	d := Pull{
+		Title:         "title",
+		Body:          "body",
+		Labels:        []string{"label"},
+		Assignees:     []string{"assignee"},
+		Reviewers:     []string{"reviewer"},
+		TeamReviewers: []string{"team"},
+		Milestone:     1,
+		Draft:         &yes,
+	}
+
+	t.Run("fills all the fields", func(t *testing.T) {
+		t.Parallel()
+
+		p := Pull{}
+		p.fillDefaults(d)
+
+		if p.Title != d.Title ||
+			p.Body != d.Body ||
+			!slices.Equal(p.Labels, d.Labels) ||
+			!slices.Equal(p.Assignees, d.Assignees) ||
+			!slices.Equal(p.Reviewers, d.Reviewers) ||
+			!slices.Equal(p.TeamReviewers, d.TeamReviewers) ||
+			p.Milestone != d.Milestone ||
+			!p.IsDraft() {
+			t.Fatalf("want %+v, got %+v", d, p)
+		}
+	})
+
+	t.Run("keeps the set fields", func(t *testing.T) {
+		t.Parallel()
+
+		p := Pull{
+			Title:  "other",
+			Labels: []string{},
+			Draft:  &no,
+		}
+		p.fillDefaults(d)
+
+		if p.Title != "other" {
+			t.Fatalf("want title 'other', got %q", p.Title)
+		}
+
+		if len(p.Labels) != 0 {
+			t.Fatalf("want no labels, got %v", p.Labels)
+		}
+
+		if p.IsDraft() {
+			t.Fatal("want not draft")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+	"github.com/nobe4/action-ln/internal/semver"
+)
+
+// latestRelease is the ref of the latest release of a repository.
+const latestRelease = "latest-release"
+
+var (
+	errResolveRef    = errors.New("failed to resolve ref")
+	errNoMatchingTag = errors.New("no tag matches")
+)
+
+// ResolveRefs replaces the `from` refs that are a semver constraint or
+// `latest-release` with the tag they resolve to.
+// Each repository and ref is only resolved once per config.
+func (l *Links) ResolveRefs(ctx context.Context, g github.Getter, c *Config) error {
+	if c.refs == nil {
+		c.refs = map[string]string{}
+	}
+
+	for _, link := range *l {
+		ref := link.From.Ref
+		if ref != latestRelease && !semver.IsConstraint(ref) {
+			continue
+		}
+
+		key := link.From.Repo.String() + "@" + ref
+
+		resolved, ok := c.refs[key]
+		if !ok {
+			var err error
+
+			if resolved, err = resolveRef(ctx, g, link.From.Repo, ref); err != nil {
+				return fmt.Errorf("%w %q for %s: %w", errResolveRef, ref, link.From, err)
+			}
+
+			c.refs[key] = resolved
+		}
+
+		log.Debug("Resolved ref", "from", link.From, "ref", resolved)
+
+		link.From.Ref = resolved
+		link.RequestedRef = ref
+	}
+
+	return nil
+}
+
+func resolveRef(ctx context.Context, g github.Getter, r github.Repo, ref string) (string, error) {
+	if ref == latestRelease {
+		release, err := g.GetLatestRelease(ctx, r)
+		if err != nil {
+			return "", err //nolint:wrapcheck // Wrapped by the caller.
+		}
+
+		return release.TagName, nil
+	}
+
+	constraint, err := semver.ParseConstraint(ref)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	tags, err := g.GetTags(ctx, r)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	best, bestVersion := "", semver.Version{}
+
+	for _, t := range tags {
+		v, err := semver.Parse(t.Name)
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+
+		if best == "" || v.Compare(bestVersion) > 0 {
+			best, bestVersion = t.Name, v
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("%w among %d tags", errNoMatchingTag, len(tags))
+	}
+
+	return best, nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestLinksResolveRefs(t *testing.T) {
+	t.Parallel()
+
+	tags := []github.Tag{
+		{Name: "v1.9.0"},
+		{Name: "v2.1.0"},
+		{Name: "v2.3.1"},
+		{Name: "v2.4.0-rc.1"},
+		{Name: "v3.0.0"},
+		{Name: "nightly"},
+	}
+
+	t.Run("keeps the other refs", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TagsHandler: func(github.Repo) ([]github.Tag, error) {
+				t.Fatal("TagsHandler should not be called in this test")
+
+				return nil, nil
+			},
+		}
+
+		l := Links{
+			{From: github.File{}},
+			{From: github.File{Ref: "main"}},
+			{From: github.File{Ref: "v2"}},
+			{From: github.File{Ref: "v2.3.1"}},
+			{From: github.File{Ref: "0123456789abcdef0123456789abcdef01234567"}},
+		}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		for _, link := range l {
+			if link.RequestedRef != "" {
+				t.Fatalf("want no resolution, got %v", link)
+			}
+		}
+	})
+
+	t.Run("resolves a constraint", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		g := gmock.Getter{
+			TagsHandler: func(github.Repo) ([]github.Tag, error) {
+				calls++
+
+				return tags, nil
+			},
+		}
+
+		l := Links{
+			{From: github.File{Path: "a", Ref: "v2.x"}},
+			{From: github.File{Path: "b", Ref: "v2.x"}},
+		}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		for _, link := range l {
+			if link.From.Ref != "v2.3.1" || link.RequestedRef != "v2.x" {
+				t.Fatalf("want v2.x resolved to v2.3.1, got %v", link)
+			}
+		}
+
+		if calls != 1 {
+			t.Fatalf("want tags to be fetched once, got %d", calls)
+		}
+	})
+
+	t.Run("resolves the latest release", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			ReleaseHandler: func(github.Repo) (github.Release, error) {
+				return github.Release{TagName: "v3.0.0"}, nil
+			},
+		}
+
+		l := Links{{From: github.File{Ref: latestRelease}}}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l[0].From.Ref != "v3.0.0" || l[0].RequestedRef != latestRelease {
+			t.Fatalf("want the latest release, got %v", l[0])
+		}
+	})
+
+	t.Run("fails to get the tags", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TagsHandler: func(github.Repo) ([]github.Tag, error) { return nil, errTest },
+		}
+
+		l := Links{{From: github.File{Ref: "^2"}}}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); !errors.Is(err, errTest) {
+			t.Fatalf("want error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("fails to get the latest release", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			ReleaseHandler: func(github.Repo) (github.Release, error) { return github.Release{}, errTest },
+		}
+
+		l := Links{{From: github.File{Ref: latestRelease}}}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); !errors.Is(err, errResolveRef) {
+			t.Fatalf("want error %v, got %v", errResolveRef, err)
+		}
+	})
+
+	t.Run("finds no matching tag", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			TagsHandler: func(github.Repo) ([]github.Tag, error) { return tags, nil },
+		}
+
+		l := Links{{From: github.File{Ref: "^4"}}}
+
+		if err := l.ResolveRefs(t.Context(), g, &Config{}); !errors.Is(err, errNoMatchingTag) {
+			t.Fatalf("want error %v, got %v", errNoMatchingTag, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/nobe4/action-ln/internal/log"
+	"github.com/nobe4/action-ln/internal/template"
+)
+
+var errRender = errors.New("failed to render")
+
+// templateData is the data available to the templates of the link.
+type templateData struct {
+	Config *Config
+	Link   *Link
+	Vars   map[string]any
+}
+
+func (l *Link) templateData(c *Config) templateData {
+	return templateData{
+		Config: c,
+		Link:   l,
+		Vars:   mergeVars(l.Vars, l.To.Vars),
+	}
+}
+
+// ShouldRender reports whether the content of `from` is a template.
+func (l *Link) ShouldRender() bool {
+	return l.Template != nil && *l.Template
+}
+
+// render executes the content of `from` as a template, so that each `to` gets
+// its own version of it.
+func (l *Link) render(c *Config) error {
+	if l.Orphan || !l.ShouldRender() {
+		return nil
+	}
+
+	if isBinary(l.From.Content) {
+		log.Warn("Not rendering binary content", "link", l)
+
+		return nil
+	}
+
+	if err := template.Update(&l.From.Content, l.templateData(c)); err != nil {
+		return fmt.Errorf("%w %q: %w", errRender, l, err)
+	}
+
+	log.Debug("Rendered content", "link", l)
+
+	return nil
+}
+
+// mergeVars returns a new map with the values of override on top of base.
+func mergeVars(base, override map[string]any) map[string]any {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	vars := maps.Clone(base)
+	if vars == nil {
+		vars = map[string]any{}
+	}
+
+	maps.Copy(vars, override)
+
+	return vars
+}
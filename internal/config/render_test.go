@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+func TestMergeVars(t *testing.T) {
+	t.Parallel()
+
+	if got := mergeVars(nil, nil); got != nil {
+		t.Fatalf("want nil, got %v", got)
+	}
+
+	base := map[string]any{"a": 1, "b": 2}
+	got := mergeVars(base, map[string]any{"b": 3})
+
+	want := map[string]any{"a": 1, "b": 3}
+	if !maps.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	if base["b"] != 2 {
+		t.Fatalf("want base to be unchanged, got %v", base)
+	}
+}
+
+func TestLinkRender(t *testing.T) {
+	t.Parallel()
+
+	yes := true
+
+	t.Run("does nothing without template", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{From: github.File{Content: "{{ .Vars.name }}"}}
+
+		if err := l.render(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.From.Content != "{{ .Vars.name }}" {
+			t.Fatalf("want the content unchanged, got %q", l.From.Content)
+		}
+	})
+
+	t.Run("renders with the destination vars", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From: github.File{Content: "{{ .Vars.name }} {{ .Vars.language }} {{ .Link.To.Repo.Repo }}"},
+			To: github.File{
+				Repo: github.Repo{Repo: "repo"},
+				Vars: map[string]any{"name": "to"},
+			},
+			Template: &yes,
+			Vars:     map[string]any{"name": "link", "language": "go"},
+		}
+
+		if err := l.render(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		want := "to go repo"
+		if l.From.Content != want {
+			t.Fatalf("want %q, got %q", want, l.From.Content)
+		}
+	})
+
+	t.Run("fails to render", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:     github.File{Content: "{{ .Missing }}"},
+			Template: &yes,
+		}
+
+		if err := l.render(&Config{}); !errors.Is(err, errRender) {
+			t.Fatalf("want error %v, got %v", errRender, err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultSectionBegin = "# BEGIN action-ln"
+	defaultSectionEnd   = "# END action-ln"
+)
+
+var errInvalidSection = errors.New("invalid section")
+
+// Section limits the sync to the block between two marker lines in `to`.
+// The rest of the file is left untouched.
+type Section struct {
+	Begin string `json:"begin" yaml:"begin"`
+	End   string `json:"end"   yaml:"end"`
+}
+
+func (s Section) markers() (string, string) {
+	begin, end := s.Begin, s.End
+
+	if begin == "" {
+		begin = defaultSectionBegin
+	}
+
+	if end == "" {
+		end = defaultSectionEnd
+	}
+
+	return begin, end
+}
+
+func (s Section) validate() error {
+	begin, end := s.markers()
+
+	if begin == end {
+		return fmt.Errorf("%w: begin and end markers must differ, got %q", errInvalidSection, begin)
+	}
+
+	if strings.Contains(begin, "\n") || strings.Contains(end, "\n") {
+		return fmt.Errorf("%w: markers must be a single line", errInvalidSection)
+	}
+
+	return nil
+}
+
+// replace returns current with the block between the markers set to block.
+// If the begin marker is missing, the block is appended to current. If only
+// the end marker is missing, it fails rather than adding a second block.
+func (s Section) replace(current, block string) (string, error) {
+	begin, end := s.markers()
+
+	if block != "" && !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+
+	if i := lineIndex(current, begin); i >= 0 {
+		// NOTE: The begin marker can be the last line, without a newline.
+		start := min(i+len(begin)+1, len(current))
+
+		j := lineIndex(current[start:], end)
+		if j < 0 {
+			return "", fmt.Errorf("%w: found %q without %q after it", errInvalidSection, begin, end)
+		}
+
+		return current[:start] + block + current[start+j:], nil
+	}
+
+	if current != "" && !strings.HasSuffix(current, "\n") {
+		current += "\n"
+	}
+
+	return current + begin + "\n" + block + end + "\n", nil
+}
+
+// lineIndex returns the index of the first line of s that is exactly line, or
+// -1 if there is none.
+func lineIndex(s, line string) int {
+	for i := 0; i <= len(s); {
+		end := strings.IndexByte(s[i:], '\n')
+		if end < 0 {
+			end = len(s)
+		} else {
+			end += i
+		}
+
+		if s[i:end] == line {
+			return i
+		}
+
+		i = end + 1
+	}
+
+	return -1
+}
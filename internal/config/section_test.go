@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestSectionValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		section Section
+		want    error
+	}{
+		{name: "default markers"},
+		{name: "custom markers", section: Section{Begin: "<!-- begin -->", End: "<!-- end -->"}},
+		{name: "same markers", section: Section{Begin: "#", End: "#"}, want: errInvalidSection},
+		{name: "multiline marker", section: Section{Begin: "a\nb"}, want: errInvalidSection},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.section.validate(); !errors.Is(err, test.want) {
+				t.Fatalf("want error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestSectionReplace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		current string
+		block   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "empty file",
+			block: "new\n",
+			want:  "# BEGIN action-ln\nnew\n# END action-ln\n",
+		},
+		{
+			name:    "no markers",
+			current: "keep",
+			block:   "new",
+			want:    "keep\n# BEGIN action-ln\nnew\n# END action-ln\n",
+		},
+		{
+			name:    "replaces the block",
+			current: "before\n# BEGIN action-ln\nold\nold\n# END action-ln\nafter\n",
+			block:   "new\n",
+			want:    "before\n# BEGIN action-ln\nnew\n# END action-ln\nafter\n",
+		},
+		{
+			name:    "empties the block",
+			current: "# BEGIN action-ln\nold\n# END action-ln\n",
+			want:    "# BEGIN action-ln\n# END action-ln\n",
+		},
+		{
+			name:    "ignores the markers inside other lines",
+			current: "echo '# BEGIN action-ln'\n",
+			block:   "new\n",
+			want:    "echo '# BEGIN action-ln'\n# BEGIN action-ln\nnew\n# END action-ln\n",
+		},
+		{
+			name:    "ignores the end marker inside another line",
+			current: "# BEGIN action-ln\nold # END action-ln\n# END action-ln",
+			block:   "new\n",
+			want:    "# BEGIN action-ln\nnew\n# END action-ln",
+		},
+		{
+			name:    "begin marker on the last line",
+			current: "keep\n# BEGIN action-ln",
+			block:   "new\n",
+			wantErr: errInvalidSection,
+		},
+		{
+			name:    "missing end marker",
+			current: "# BEGIN action-ln\nold\n",
+			block:   "new\n",
+			wantErr: errInvalidSection,
+		},
+		{
+			name:    "end marker on the last line",
+			current: "# BEGIN action-ln\n# END action-ln",
+			block:   "new\n",
+			want:    "# BEGIN action-ln\nnew\n# END action-ln",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := (Section{}).replace(test.current, test.block)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinkSection(t *testing.T) {
+	t.Parallel()
+
+	section := &Section{Begin: "<!-- begin -->", End: "<!-- end -->"}
+	synced := "local\n<!-- begin -->\nshared\n<!-- end -->\n"
+
+	t.Run("only compares the block", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				f.Content = synced
+
+				return nil
+			},
+		}
+
+		l := &Link{
+			From:    github.File{Content: "shared\n"},
+			To:      github.File{Content: "other local\n<!-- begin -->\nold\n<!-- end -->\n"},
+			Section: section,
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, github.Branch{Name: "head"})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if needUpdate {
+			t.Fatal("want no update")
+		}
+	})
+
+	t.Run("writes the block in the head content", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.GetterUpdater{
+			Getter: gmock.Getter{
+				FileHandler: func(f *github.File) error {
+					f.Content = "local\n<!-- begin -->\nold\n<!-- end -->\n"
+
+					return nil
+				},
+			},
+			Updater: gmock.Updater{
+				BlobHandler: func(_ github.Repo, c string) (string, error) {
+					if c != synced {
+						t.Fatalf("want content %q, got %q", synced, c)
+					}
+
+					return "blob", nil
+				},
+			},
+		}
+
+		l := &Link{
+			From:    github.File{Content: "shared"},
+			To:      github.File{Path: "README.md"},
+			Section: section,
+		}
+
+		needUpdate, err := l.NeedUpdate(t.Context(), g, github.Branch{Name: "head"})
+		if err != nil || !needUpdate {
+			t.Fatalf("want an update, got %v, %v", needUpdate, err)
+		}
+
+		if _, err := l.treeEntry(t.Context(), g, ""); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	})
+}
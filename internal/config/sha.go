@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+// fileSHA returns the blob SHA of f, computed from its content when the API
+// didn't provide it.
+func fileSHA(f github.File) string {
+	if f.SHA != "" || f.Content == "" {
+		return f.SHA
+	}
+
+	return github.BlobSHA(f.Content)
+}
+
+// needsCurrent reports whether the content of `to` is needed to know what to
+// write in it.
+func (l *Link) needsCurrent() bool {
+	return l.Section != nil || l.Merge != nil
+}
+
+// wantSHA returns the blob SHA `to` should have, given its current state.
+// Content is only fetched when it's needed to compute it.
+func (l *Link) wantSHA(ctx context.Context, g github.Getter, to *github.File) (string, error) {
+	if l.verbatim() {
+		return fileSHA(l.From), nil
+	}
+
+	if l.needsCurrent() && to.SHA != "" && to.Content == "" {
+		if err := g.GetFile(ctx, to); err != nil {
+			return "", fmt.Errorf("%w %s: %w", errMissingTo, to, err)
+		}
+	}
+
+	content, err := l.content(to.Content)
+	if err != nil {
+		return "", err
+	}
+
+	return github.BlobSHA(content), nil
+}
+
+// loadFrom gets the content of `from`, if only its metadata was populated.
+func (l *Link) loadFrom(ctx context.Context, g github.Getter) error {
+	if !l.verbatim() || l.From.Content != "" || l.From.SHA == "" {
+		return nil
+	}
+
+	if err := g.GetFile(ctx, &l.From); err != nil {
+		return fmt.Errorf("%w %s: %w", errMissingFrom, l.From, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nobe4/action-ln/internal/github"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+var errCheckSync = errors.New("failed to check sync")
+
+// InSync reports whether the `to` file at ref already matches the link, i.e.
+// updating it would not change anything.
+func (l *Link) InSync(ctx context.Context, g github.Getter, ref string) (bool, error) {
+	to := &github.File{
+		Repo: l.To.Repo,
+		Path: l.To.Path,
+		Ref:  ref,
+	}
+
+	if err := g.GetFileInfo(ctx, to); err != nil {
+		if errors.Is(err, github.ErrMissingFile) {
+			return l.Orphan, nil
+		}
+
+		return false, fmt.Errorf("%w %s: %w", errCheckSync, to, err)
+	}
+
+	if l.Orphan {
+		return false, nil
+	}
+
+	want, err := l.wantSHA(ctx, g, to)
+	if err != nil {
+		return false, err
+	}
+
+	return want == fileSHA(*to), nil
+}
+
+// InSync reports whether all the links are in sync at ref.
+// Links that are not known to be up to date are never in sync.
+func (l *Links) InSync(ctx context.Context, g github.Getter, ref string) (bool, error) {
+	for _, link := range *l {
+		if link.Status != StatusUpdateNotNeeded {
+			return false, nil
+		}
+
+		inSync, err := link.InSync(ctx, g, ref)
+		if err != nil {
+			return false, err
+		}
+
+		if !inSync {
+			log.Debug("Link is not in sync", "link", link, "ref", ref)
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// RefreshTo reads the `to` files again at ref, e.g. after the head branch was
+// reset, so that NeedUpdate doesn't rely on the previous content.
+func (l *Links) RefreshTo(ctx context.Context, g github.Getter, ref string) error {
+	for _, link := range *l {
+		to := github.File{
+			Repo: link.To.Repo,
+			Path: link.To.Path,
+			Ref:  ref,
+			Vars: link.To.Vars,
+		}
+
+		if err := g.GetFileInfo(ctx, &to); err != nil && !errors.Is(err, github.ErrMissingFile) {
+			return fmt.Errorf("%w %s: %w", errMissingTo, to, err)
+		}
+
+		link.To = to
+	}
+
+	return nil
+}
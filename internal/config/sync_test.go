@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+	gmock "github.com/nobe4/action-ln/internal/github/mock"
+)
+
+func TestLinkInSync(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		FileHandler: func(f *github.File) error {
+			if f.Ref != "main" {
+				t.Fatalf("want ref 'main', got %q", f.Ref)
+			}
+
+			switch f.Path {
+			case "same":
+				f.Content = "content"
+			case "differs":
+				f.Content = "other"
+			case "missing":
+				return github.ErrMissingFile
+			default:
+				return errTest
+			}
+
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		orphan bool
+		want   bool
+		err    error
+	}{
+		{name: "same content", path: "same", want: true},
+		{name: "different content", path: "differs"},
+		{name: "missing file", path: "missing"},
+		{name: "orphan still exists", path: "same", orphan: true},
+		{name: "orphan is deleted", path: "missing", orphan: true, want: true},
+		{name: "fails to get the file", path: "error", err: errCheckSync},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &Link{
+				From:   github.File{Content: "content"},
+				To:     github.File{Path: test.path},
+				Orphan: test.orphan,
+			}
+
+			got, err := l.InSync(t.Context(), g, "main")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("want error %v, got %v", test.err, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinksInSync(t *testing.T) {
+	t.Parallel()
+
+	g := gmock.Getter{
+		FileHandler: func(f *github.File) error {
+			f.Content = "content"
+
+			return nil
+		},
+	}
+
+	t.Run("in sync", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{
+			{From: github.File{Content: "content"}, Status: StatusUpdateNotNeeded},
+			{From: github.File{Content: "content"}, Status: StatusUpdateNotNeeded},
+		}
+
+		got, err := l.InSync(t.Context(), g, "main")
+		if err != nil || !got {
+			t.Fatalf("want in sync, got %v, %v", got, err)
+		}
+	})
+
+	t.Run("a link failed", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{
+			{From: github.File{Content: "content"}, Status: StatusUpdateNotNeeded},
+			{From: github.File{Content: "content"}, Status: StatusFailedToCheck},
+		}
+
+		got, err := l.InSync(t.Context(), g, "main")
+		if err != nil || got {
+			t.Fatalf("want not in sync, got %v, %v", got, err)
+		}
+	})
+
+	t.Run("a link differs", func(t *testing.T) {
+		t.Parallel()
+
+		l := Links{
+			{From: github.File{Content: "content"}, Status: StatusUpdateNotNeeded},
+			{From: github.File{Content: "other"}, Status: StatusUpdateNotNeeded},
+		}
+
+		got, err := l.InSync(t.Context(), g, "main")
+		if err != nil || got {
+			t.Fatalf("want not in sync, got %v, %v", got, err)
+		}
+	})
+}
+
+func TestLinksRefreshTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(*github.File) error { return errTest },
+		}
+
+		l := Links{{To: github.File{Path: "a"}}}
+
+		if err := l.RefreshTo(t.Context(), g, "head"); !errors.Is(err, errMissingTo) {
+			t.Fatalf("want error %v, got %v", errMissingTo, err)
+		}
+	})
+
+	t.Run("refreshes the files", func(t *testing.T) {
+		t.Parallel()
+
+		g := gmock.Getter{
+			FileHandler: func(f *github.File) error {
+				if f.Ref != "head" {
+					t.Fatalf("want ref 'head', got %q", f.Ref)
+				}
+
+				if f.Path == "missing" {
+					return github.ErrMissingFile
+				}
+
+				f.Content = "new"
+				f.SHA = "sha"
+
+				return nil
+			},
+		}
+
+		l := Links{
+			{To: github.File{Path: "a", Content: "old", SHA: "old"}},
+			{To: github.File{Path: "missing", Content: "old", SHA: "old"}},
+		}
+
+		if err := l.RefreshTo(t.Context(), g, "head"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l[0].To.Content != "new" || l[0].To.SHA != "sha" {
+			t.Fatalf("want the new content, got %+v", l[0].To)
+		}
+
+		if l[1].To.Content != "" || l[1].To.SHA != "" {
+			t.Fatalf("want no content, got %+v", l[1].To)
+		}
+	})
+}
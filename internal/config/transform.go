@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/log"
+	"github.com/nobe4/action-ln/internal/template"
+)
+
+var (
+	errInvalidTransform = errors.New("invalid transform")
+	errTransform        = errors.New("failed to transform")
+)
+
+// Transform changes the content of `from` before it is written to `to`.
+// Exactly one of its fields must be set.
+type Transform struct {
+	// Replace replaces all the matches of a regular expression.
+	Replace *Replace `json:"replace,omitempty" yaml:"replace,omitempty"`
+
+	// Prepend adds text at the beginning of the content.
+	Prepend string `json:"prepend,omitempty" yaml:"prepend,omitempty"`
+
+	// Append adds text at the end of the content.
+	Append string `json:"append,omitempty" yaml:"append,omitempty"`
+
+	// Lines only keeps a range of lines.
+	Lines *Lines `json:"lines,omitempty" yaml:"lines,omitempty"`
+
+	// Template renders the content as a template, see Link.templateData.
+	Template bool `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+type Replace struct {
+	// Pattern is a regular expression, see https://pkg.go.dev/regexp/syntax.
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// With can reference the groups with `$1` or `${name}`.
+	With string `json:"with" yaml:"with"`
+}
+
+// Lines is a 1-indexed, inclusive, range of lines.
+// An unset start is the first line, an unset end is the last line.
+type Lines struct {
+	Start int `json:"start,omitempty" yaml:"start,omitempty"`
+	End   int `json:"end,omitempty"   yaml:"end,omitempty"`
+}
+
+func (t Transform) validate() error {
+	set := 0
+
+	for _, ok := range []bool{t.Replace != nil, t.Prepend != "", t.Append != "", t.Lines != nil, t.Template} {
+		if ok {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return fmt.Errorf("%w: want exactly one transformation, got %d", errInvalidTransform, set)
+	}
+
+	if t.Replace != nil {
+		if _, err := regexp.Compile(t.Replace.Pattern); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidTransform, err)
+		}
+	}
+
+	if t.Lines != nil {
+		if t.Lines.Start < 0 || t.Lines.End < 0 || (t.Lines.End > 0 && t.Lines.End < t.Lines.Start) {
+			return fmt.Errorf("%w: invalid lines %d-%d", errInvalidTransform, t.Lines.Start, t.Lines.End)
+		}
+	}
+
+	return nil
+}
+
+func (t Transform) apply(s string, data any) (string, error) {
+	switch {
+	case t.Replace != nil:
+		return regexp.MustCompile(t.Replace.Pattern).ReplaceAllString(s, t.Replace.With), nil
+
+	case t.Prepend != "":
+		return t.Prepend + s, nil
+
+	case t.Append != "":
+		return s + t.Append, nil
+
+	case t.Lines != nil:
+		return t.Lines.extract(s), nil
+
+	case t.Template:
+		if err := template.Update(&s, data); err != nil {
+			return "", err //nolint:wrapcheck // Wrapped by the caller.
+		}
+
+		return s, nil
+	}
+
+	return s, nil
+}
+
+func (l Lines) extract(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+
+	// NOTE: SplitAfter leaves an empty string after a trailing newline.
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	start, end := max(l.Start, 1), len(lines)
+	if l.End > 0 {
+		end = min(l.End, end)
+	}
+
+	if start > end {
+		return ""
+	}
+
+	return strings.Join(lines[start-1:end], "")
+}
+
+// transform applies the transforms, in order, to the content of `from`.
+func (l *Link) transform(c *Config) error {
+	if l.Orphan || len(l.Transforms) == 0 {
+		return nil
+	}
+
+	if isBinary(l.From.Content) {
+		log.Warn("Not transforming binary content", "link", l)
+
+		return nil
+	}
+
+	data := l.templateData(c)
+
+	content := l.From.Content
+
+	for i, t := range l.Transforms {
+		var err error
+
+		if content, err = t.apply(content, data); err != nil {
+			return fmt.Errorf("%w %d for %q: %w", errTransform, i, l, err)
+		}
+	}
+
+	log.Debug("Transformed content", "link", l, "transforms", l.Transforms)
+
+	l.From.Content = content
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+func TestTransformValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		transform Transform
+		want      error
+	}{
+		{name: "replace", transform: Transform{Replace: &Replace{Pattern: "a+"}}},
+		{name: "prepend", transform: Transform{Prepend: "a"}},
+		{name: "lines", transform: Transform{Lines: &Lines{Start: 1, End: 2}}},
+		{name: "none", want: errInvalidTransform},
+		{name: "many", transform: Transform{Prepend: "a", Append: "b"}, want: errInvalidTransform},
+		{name: "invalid pattern", transform: Transform{Replace: &Replace{Pattern: "("}}, want: errInvalidTransform},
+		{name: "invalid lines", transform: Transform{Lines: &Lines{Start: 3, End: 2}}, want: errInvalidTransform},
+		{name: "negative lines", transform: Transform{Lines: &Lines{Start: -1}}, want: errInvalidTransform},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.transform.validate(); !errors.Is(err, test.want) {
+				t.Fatalf("want error %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestLinesExtract(t *testing.T) {
+	t.Parallel()
+
+	content := "1\n2\n3\n4\n"
+
+	tests := []struct {
+		lines Lines
+		want  string
+	}{
+		{lines: Lines{}, want: content},
+		{lines: Lines{Start: 2}, want: "2\n3\n4\n"},
+		{lines: Lines{End: 2}, want: "1\n2\n"},
+		{lines: Lines{Start: 2, End: 3}, want: "2\n3\n"},
+		{lines: Lines{Start: 3, End: 10}, want: "3\n4\n"},
+		{lines: Lines{Start: 10}, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.lines.extract(content); got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLinkTransform(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies the transforms in order", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From: github.File{Path: "a.txt", Content: "header\nhello from_repo\n"},
+			To:   github.File{Repo: github.Repo{Repo: "to_repo"}},
+			Transforms: []Transform{
+				{Lines: &Lines{Start: 2}},
+				{Replace: &Replace{Pattern: "from_(repo)", With: "{{ .Link.To.Repo.Repo }} $1"}},
+				{Template: true},
+				{Prepend: "# synced\n"},
+				{Append: "# end\n"},
+			},
+		}
+
+		if err := l.transform(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		want := "# synced\nhello to_repo repo\n# end\n"
+		if l.From.Content != want {
+			t.Fatalf("want %q, got %q", want, l.From.Content)
+		}
+	})
+
+	t.Run("fails to render the template", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			From:       github.File{Content: "{{ .Missing }}"},
+			Transforms: []Transform{{Template: true}},
+		}
+
+		if err := l.transform(&Config{}); !errors.Is(err, errTransform) {
+			t.Fatalf("want error %v, got %v", errTransform, err)
+		}
+	})
+
+	t.Run("skips the orphans", func(t *testing.T) {
+		t.Parallel()
+
+		l := &Link{
+			Orphan:     true,
+			Transforms: []Transform{{Prepend: "a"}},
+		}
+
+		if err := l.transform(&Config{}); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if l.From.Content != "" {
+			t.Fatalf("want no content, got %q", l.From.Content)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var errInvalidTemplate = errors.New("invalid template")
+
+// validateTemplate checks that s can be used by format.Formatter.Format.
+func validateTemplate(name, s string) error {
+	if _, err := htmltemplate.New(name).Parse(s); err != nil {
+		return fmt.Errorf("%w %q: %w", errInvalidTemplate, name, err)
+	}
+
+	return nil
+}
+
+// validateTextTemplate checks that s can be used by format.Formatter.FormatText.
+func validateTextTemplate(name, s string) error {
+	if _, err := template.New(name).Parse(s); err != nil {
+		return fmt.Errorf("%w %q: %w", errInvalidTemplate, name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Package diff implements a line-based unified diff, using Myers' algorithm.
+
+http://www.xmailserver.org/diff2.pdf
+*/
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// context is the number of unchanged lines shown around the changes.
+	context = 3
+
+	// maxEdits caps the size of the diff, to bound the memory it takes.
+	maxEdits = 2000
+
+	// TooLarge replaces diffs above maxEdits.
+	TooLarge = "Diff too large to be shown.\n"
+)
+
+const (
+	opEqual  = ' '
+	opDelete = '-'
+	opInsert = '+'
+)
+
+type edit struct {
+	op   byte
+	line string
+}
+
+// Unified returns the unified diff turning a into b, with the names in the
+// header. It is empty if a and b are equal.
+func Unified(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	edits, ok := myers(lines(a), lines(b))
+	if !ok {
+		return TooLarge
+	}
+
+	out := strings.Builder{}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	writeHunks(&out, edits)
+
+	return out.String()
+}
+
+// lines splits s after each newline, the last line may not end with one.
+func lines(s string) []string {
+	l := strings.SplitAfter(s, "\n")
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+
+	return l
+}
+
+// myers returns the shortest list of edits turning a into b.
+// It keeps, for each number of edits d, the furthest reaching x on each
+// diagonal k = x - y, and backtracks through them.
+func myers(a, b []string) ([]edit, bool) {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	trace := [][]int{}
+
+	for d := 0; d <= n+m; d++ {
+		if d > maxEdits {
+			return nil, false
+		}
+
+		// Only the diagonals reachable in d edits are needed later.
+		trace = append(trace, append([]int{}, v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k+1]
+			if k != -d && (k == d || v[offset+k-1] >= v[offset+k+1]) {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace), true
+			}
+		}
+	}
+
+	return backtrack(a, b, trace), true
+}
+
+func backtrack(a, b []string, trace [][]int) []edit {
+	x, y := len(a), len(b)
+	edits := []edit{}
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+		k := x - y
+
+		prevK := k + 1
+		if k != -d && (k == d || at(k-1) >= at(k+1)) {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: opEqual, line: a[x]})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			edits = append(edits, edit{op: opInsert, line: b[y-1]})
+		} else {
+			edits = append(edits, edit{op: opDelete, line: a[x-1]})
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// writeHunks writes the changes with their context, merging the hunks whose
+// contexts overlap.
+func writeHunks(out *strings.Builder, edits []edit) {
+	// aPos[i] and bPos[i] are the lines of a and b before edits[i].
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+
+		if e.op != opInsert {
+			aPos[i+1]++
+		}
+
+		if e.op != opDelete {
+			bPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		change := i
+		for change < len(edits) && edits[change].op == opEqual {
+			change++
+		}
+
+		if change == len(edits) {
+			return
+		}
+
+		start := max(change-context, i)
+		end := hunkEnd(edits, change)
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]),
+			hunkRange(bPos[start], bPos[end]),
+		)
+
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+}
+
+// hunkEnd returns the end of the hunk with the change at i.
+func hunkEnd(edits []edit, i int) int {
+	for {
+		for i < len(edits) && edits[i].op != opEqual {
+			i++
+		}
+
+		next := i
+		for next < len(edits) && edits[next].op == opEqual {
+			next++
+		}
+
+		if next == len(edits) || next-i > 2*context {
+			return min(i+context, len(edits))
+		}
+
+		i = next
+	}
+}
+
+// hunkRange formats the lines between start and end, 0-indexed.
+// An empty range points at the line before it.
+func hunkRange(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
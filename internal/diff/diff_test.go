@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{name: "equal", a: "a\nb\n", b: "a\nb\n"},
+
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+
+		{
+			name: "to empty",
+			a:    "a\n",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1,1 +0,0 @@\n-a\n",
+		},
+
+		{
+			name: "change",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+
+		{
+			name: "missing newline",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "0\n2\n3\n4\n5\n6\n7\n8\n9\n11\n",
+			want: "--- a\n+++ b\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+11\n",
+		},
+
+		{
+			name: "merged hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n",
+			b:    "0\n2\n3\n4\n5\n6\n8\n",
+			want: "--- a\n+++ b\n@@ -1,7 +1,7 @@\n-1\n+0\n 2\n 3\n 4\n 5\n 6\n-7\n+8\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Unified("a", "b", test.a, test.b); got != test.want {
+				t.Fatalf("want\n%s\ngot\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestUnifiedTooLarge(t *testing.T) {
+	t.Parallel()
+
+	a := strings.Repeat("a\n", maxEdits)
+	b := strings.Repeat("b\n", maxEdits)
+
+	if got := Unified("a", "b", a, b); got != TooLarge {
+		t.Fatalf("want %q, got %q", TooLarge, got)
+	}
+}
@@ -23,10 +23,14 @@ var (
 	ErrNoToken            = errors.New("github token not found")
 	ErrNoRepo             = errors.New("github repository not found")
 	ErrInvalidRepo        = errors.New("github repository invalid: want owner/repo")
+	ErrInvalidMode        = errors.New("mode invalid: want sync or report")
+	ErrLocalNoop          = errors.New("noop is not supported on local repositories")
 )
 
 const (
 	defaultEndpoint = "https://api.github.com"
+	defaultGitLab   = "https://gitlab.com/api/v4"
+	localScheme     = "file://"
 	defaultServer   = "https://github.com"
 	defaultConfig   = ".ln-config.yaml"
 	defaultRunID    = ""
@@ -34,21 +38,47 @@ const (
 	missing         = "[missing]"
 )
 
+const (
+	// ModeSync writes the links and opens the pull requests.
+	ModeSync = "sync"
+
+	// ModeReport only reports how the links drifted, without writing anything.
+	ModeReport = "report"
+)
+
 type App struct {
 	ID         string `json:"app_id"`          // INPUT_APP_ID
 	PrivateKey string `json:"app_private_key"` // INPUT_APP_PRIVATE_KEY
 	InstallID  string `json:"app_install_id"`  // INPUT_APP_INSTALL_ID
 }
 
+// Offline serves the API from a directory, see client/offline.
+type Offline struct {
+	Dir     string `json:"dir"`     // INPUT_OFFLINE_DIR
+	Journal string `json:"journal"` // INPUT_OFFLINE_JOURNAL
+}
+
+// GitLab hosts the `gitlab:` files, see backend/gitlab.
+type GitLab struct {
+	Endpoint string `json:"endpoint"` // INPUT_GITLAB_ENDPOINT
+	Token    string `json:"token"`    // INPUT_GITLAB_TOKEN
+}
+
 type Environment struct {
-	Noop        bool        `json:"noop"`     // INPUT_NOOP
-	Token       string      `json:"token"`    // GITHUB_TOKEN / INPUT_TOKEN
-	Repo        github.Repo `json:"repo"`     // GITHUB_REPOSITORY
-	Server      string      `json:"server"`   // GITHUB_SERVER_URL
-	Endpoint    string      `json:"endpoint"` // GITHUB_API_URL
-	RunID       string      `json:"run_id"`   // GITHUB_RUN_ID
-	Config      string      `json:"config"`   // INPUT_CONFIG
+	Noop        bool        `json:"noop"`        // INPUT_NOOP
+	NoopOutput  string      `json:"noop_output"` // INPUT_NOOP_OUTPUT
+	Offline     Offline     `json:"offline"`
+	Mode        string      `json:"mode"`         // INPUT_MODE
+	ReportDir   string      `json:"report_dir"`   // INPUT_REPORT_DIR
+	StepSummary string      `json:"step_summary"` // GITHUB_STEP_SUMMARY
+	Token       string      `json:"token"`        // GITHUB_TOKEN / INPUT_TOKEN
+	Repo        github.Repo `json:"repo"`         // GITHUB_REPOSITORY
+	Server      string      `json:"server"`       // GITHUB_SERVER_URL
+	Endpoint    string      `json:"endpoint"`     // INPUT_ENDPOINT / GITHUB_API_URL
+	RunID       string      `json:"run_id"`       // GITHUB_RUN_ID
+	Config      string      `json:"config"`       // INPUT_CONFIG
 	App         App         `json:"app"`
+	GitLab      GitLab      `json:"gitlab"`
 	OnAction    bool        `json:"on_action"`
 	ExecURL     string      `json:"exec_url"`
 	Debug       bool        `json:"debug"`        // RUNNER_DEBUG
@@ -61,6 +91,7 @@ func (e Environment) String() string {
 	e.App.ID = missingOrRedacted(e.App.ID)
 	e.App.PrivateKey = missingOrRedacted(e.App.PrivateKey)
 	e.App.InstallID = missingOrRedacted(e.App.InstallID)
+	e.GitLab.Token = missingOrRedacted(e.GitLab.Token)
 
 	out, err := json.MarshalIndent(e, "", "  ")
 	if err != nil {
@@ -87,7 +118,11 @@ func Parse() (Environment, error) {
 
 	var err error
 
-	if e.Token, err = parseToken(); err != nil {
+	e.Offline = parseOffline()
+	e.Endpoint = parseEndpoint()
+
+	// NOTE: Offline and local repositories don't need any token.
+	if e.Token, err = parseToken(); err != nil && e.Offline.Dir == "" && !e.Local() {
 		return e, fmt.Errorf("%w: %w", ErrInvalidEnvironment, err)
 	}
 
@@ -95,25 +130,66 @@ func Parse() (Environment, error) {
 		return e, fmt.Errorf("%w: %w", ErrInvalidEnvironment, err)
 	}
 
-	e.Noop = parseNoop()
-	e.Endpoint = parseEndpoint()
+	if e.Mode, err = parseMode(); err != nil {
+		return e, fmt.Errorf("%w: %w", ErrInvalidEnvironment, err)
+	}
+
+	// NOTE: Offline never writes anything.
+	e.Noop = parseNoop() || e.Offline.Dir != ""
+	e.NoopOutput = parseNoopOutput()
+	e.ReportDir = parseReportDir()
+	e.StepSummary = parseStepSummary()
 	e.Server = parseServer()
 	e.RunID = parseRunID()
 	e.Config = parseConfig()
 	e.App = parseApp()
+	e.GitLab = parseGitLab()
 	e.OnAction = parseOnAction()
 	e.Debug = parseDebug()
 	e.LocalConfig = parseLocalConfig()
 
 	e.ExecURL = fmt.Sprintf("%s/%s/actions/runs/%s", e.Server, e.Repo, e.RunID)
 
+	// NOTE: Noop only intercepts the API requests.
+	if e.Local() && e.Noop {
+		return e, fmt.Errorf("%w: %w", ErrInvalidEnvironment, ErrLocalNoop)
+	}
+
 	return e, nil
 }
 
+// Local reports whether the endpoint is a directory of git repositories, see
+// backend/git.
+func (e Environment) Local() bool {
+	return strings.HasPrefix(e.Endpoint, localScheme)
+}
+
 func parseNoop() bool {
 	return truthy(os.Getenv("INPUT_NOOP"))
 }
 
+func parseMode() (string, error) {
+	switch mode := strings.ToLower(os.Getenv("INPUT_MODE")); mode {
+	case "", ModeSync:
+		return ModeSync, nil
+	case ModeReport:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidMode, mode)
+	}
+}
+
+func parseNoopOutput() string {
+	return os.Getenv("INPUT_NOOP_OUTPUT")
+}
+
+func parseOffline() Offline {
+	return Offline{
+		Dir:     os.Getenv("INPUT_OFFLINE_DIR"),
+		Journal: os.Getenv("INPUT_OFFLINE_JOURNAL"),
+	}
+}
+
 func parseToken() (string, error) {
 	if token := os.Getenv("INPUT_TOKEN"); token != "" {
 		return token, nil
@@ -145,6 +221,10 @@ func parseRepo() (github.Repo, error) {
 }
 
 func parseEndpoint() string {
+	if endpoint := os.Getenv("INPUT_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+
 	if endpoint := os.Getenv("GITHUB_API_URL"); endpoint != "" {
 		return endpoint
 	}
@@ -184,6 +264,19 @@ func parseApp() App {
 	}
 }
 
+func parseGitLab() GitLab {
+	g := GitLab{
+		Endpoint: os.Getenv("INPUT_GITLAB_ENDPOINT"),
+		Token:    os.Getenv("INPUT_GITLAB_TOKEN"),
+	}
+
+	if g.Endpoint == "" {
+		g.Endpoint = defaultGitLab
+	}
+
+	return g
+}
+
 func parseOnAction() bool {
 	return os.Getenv("GITHUB_RUN_ID") != ""
 }
@@ -196,6 +289,14 @@ func parseLocalConfig() string {
 	return os.Getenv("INPUT_LOCAL_CONFIG")
 }
 
+func parseReportDir() string {
+	return os.Getenv("INPUT_REPORT_DIR")
+}
+
+func parseStepSummary() string {
+	return os.Getenv("GITHUB_STEP_SUMMARY")
+}
+
 func truthy(s string) bool {
 	switch strings.ToLower(s) {
 	case "1", "true", "yes":
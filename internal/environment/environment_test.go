@@ -19,6 +19,34 @@ func TestParseNoop(t *testing.T) {
 	}
 }
 
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+		err  error
+	}{
+		{env: "", want: ModeSync},
+		{env: "sync", want: ModeSync},
+		{env: "Report", want: ModeReport},
+		{env: "other", err: ErrInvalidMode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.env, func(t *testing.T) {
+			t.Setenv("INPUT_MODE", test.env)
+
+			got, err := parseMode()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("want error %v, got %v", test.err, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
 func TestParseToken(t *testing.T) {
 	const want = "token"
 
@@ -97,6 +125,7 @@ func TestParseEndpoint(t *testing.T) {
 	t.Run("gets the default", func(t *testing.T) {
 		// Need to force an empty value to not conflict with GitHub Action's Env
 		t.Setenv("GITHUB_REPOSITORY", "")
+		t.Setenv("INPUT_ENDPOINT", "")
 
 		got := parseEndpoint()
 		if defaultEndpoint != got {
@@ -107,11 +136,27 @@ func TestParseEndpoint(t *testing.T) {
 	t.Run("gets the set endpoint", func(t *testing.T) {
 		want := "https://example.com"
 		t.Setenv("GITHUB_API_URL", want)
+		t.Setenv("INPUT_ENDPOINT", "")
+
+		got := parseEndpoint()
+		if want != got {
+			t.Fatalf("want %v but got %v", want, got)
+		}
+	})
+
+	t.Run("prefers the input", func(t *testing.T) {
+		want := "file:///srv/git"
+		t.Setenv("GITHUB_API_URL", "https://example.com")
+		t.Setenv("INPUT_ENDPOINT", want)
 
 		got := parseEndpoint()
 		if want != got {
 			t.Fatalf("want %v but got %v", want, got)
 		}
+
+		if !(Environment{Endpoint: got}).Local() {
+			t.Fatalf("want %v to be local", got)
+		}
 	})
 }
 
@@ -202,6 +247,33 @@ func TestParseApp(t *testing.T) {
 	}
 }
 
+func TestParseOffline(t *testing.T) {
+	t.Setenv("INPUT_OFFLINE_DIR", "dir")
+	t.Setenv("INPUT_OFFLINE_JOURNAL", "journal")
+
+	want := Offline{Dir: "dir", Journal: "journal"}
+	if got := parseOffline(); want != got {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	t.Setenv("INPUT_GITLAB_ENDPOINT", "")
+	t.Setenv("INPUT_GITLAB_TOKEN", "token")
+
+	want := GitLab{Endpoint: defaultGitLab, Token: "token"}
+	if got := parseGitLab(); want != got {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+
+	t.Setenv("INPUT_GITLAB_ENDPOINT", "https://gitlab.example.com/api/v4")
+
+	want.Endpoint = "https://gitlab.example.com/api/v4"
+	if got := parseGitLab(); want != got {
+		t.Fatalf("want %v but got %v", want, got)
+	}
+}
+
 func TestParseOnAction(t *testing.T) {
 	t.Setenv("GITHUB_RUN_ID", "")
 
@@ -2,8 +2,10 @@ package context
 
 import (
 	"fmt"
-	"html/template"
+	htmltemplate "html/template"
+	"io"
 	"strings"
+	"text/template"
 
 	"github.com/nobe4/action-ln/internal/config"
 	"github.com/nobe4/action-ln/internal/environment"
@@ -21,12 +23,30 @@ func New(c *config.Config, e environment.Environment) Formatter {
 	}
 }
 
+type executer interface {
+	Execute(w io.Writer, data any) error
+}
+
 func (f Formatter) Format(tmpl string, data any) (string, error) {
+	t, err := htmltemplate.New("").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return f.execute(t, data)
+}
+
+// FormatText doesn't escape the output, as commits and branches aren't HTML.
+func (f Formatter) FormatText(tmpl string, data any) (string, error) {
 	t, err := template.New("").Parse(tmpl)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
+	return f.execute(t, data)
+}
+
+func (f Formatter) execute(t executer, data any) (string, error) {
 	out := strings.Builder{}
 	d := struct {
 		Data        any
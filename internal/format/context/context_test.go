@@ -0,0 +1,49 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/config"
+	"github.com/nobe4/action-ln/internal/environment"
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+func TestFormatter(t *testing.T) {
+	t.Parallel()
+
+	f := New(config.New(github.File{}, github.Repo{}), environment.Environment{})
+
+	t.Run("escapes HTML", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := f.Format("{{ .Data }}", "Don't")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := "Don&#39;t"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("keeps text as is", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := f.FormatText("{{ .Data }}", "Don't <b>")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if want := "Don't <b>"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("fails to parse", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := f.FormatText("{{ end }}", nil); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -1,5 +1,10 @@
 package format
 
 type Formatter interface {
+	// Format renders an HTML template, for the pull request body.
 	Format(tmpl string, data any) (string, error)
+
+	// FormatText renders a text template, for the commit, the branch, and the
+	// pull request title.
+	FormatText(tmpl string, data any) (string, error)
 }
@@ -9,3 +9,7 @@ func New() Formatter {
 func (Formatter) Format(tmpl string, _ any) (string, error) {
 	return tmpl, nil
 }
+
+func (Formatter) FormatText(tmpl string, _ any) (string, error) {
+	return tmpl, nil
+}
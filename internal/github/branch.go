@@ -17,6 +17,8 @@ var (
 	ErrCreateBranch = errors.New("failed to create branch")
 	ErrBranchExists = errors.New("branch already exist")
 	ErrDeleteBranch = errors.New("failed to delete branch")
+	ErrUpdateBranch = errors.New("failed to update branch")
+	ErrResetBranch  = errors.New("failed to reset branch")
 )
 
 type Commit struct {
@@ -54,6 +56,8 @@ func (g *GitHub) GetBranch(ctx context.Context, r Repo, name string) (Branch, er
 func (g *GitHub) CreateBranch(ctx context.Context, r Repo, name, sha string) (Branch, error) {
 	log.Debug("Create branch", "repo", r, "name", name, "sha", sha)
 
+	g.forget(r)
+
 	b := Branch{
 		Name: name,
 		Commit: Commit{
@@ -89,6 +93,8 @@ func (g *GitHub) CreateBranch(ctx context.Context, r Repo, name, sha string) (Br
 
 // https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#delete-a-reference
 func (g *GitHub) DeleteBranch(ctx context.Context, r Repo, name string) error {
+	g.forget(r)
+
 	path := fmt.Sprintf("/repos/%s/git/refs/heads/%s", r, name)
 
 	if _, err := g.req(ctx, http.MethodDelete, path, nil, nil); err != nil {
@@ -98,6 +104,57 @@ func (g *GitHub) DeleteBranch(ctx context.Context, r Repo, name string) error {
 	return nil
 }
 
+// https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#update-a-reference
+func (g *GitHub) UpdateBranch(ctx context.Context, r Repo, name, sha string) (Branch, error) {
+	log.Debug("Update branch", "repo", r, "name", name, "sha", sha)
+
+	g.forget(r)
+
+	path := fmt.Sprintf("/repos/%s/git/refs/heads/%s", r, name)
+
+	body, err := json.Marshal(struct {
+		SHA string `json:"sha"`
+	}{
+		SHA: sha,
+	})
+	if err != nil {
+		return Branch{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	if _, err := g.req(ctx, http.MethodPatch, path, bytes.NewReader(body), nil); err != nil {
+		return Branch{}, fmt.Errorf("%w: %w", ErrUpdateBranch, err)
+	}
+
+	return Branch{Name: name, Commit: Commit{SHA: sha}}, nil
+}
+
+// ResetBranch force-updates the branch to sha, dropping its own commits.
+// https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#update-a-reference
+func (g *GitHub) ResetBranch(ctx context.Context, r Repo, name, sha string) (Branch, error) {
+	log.Debug("Reset branch", "repo", r, "name", name, "sha", sha)
+
+	g.forget(r)
+
+	path := fmt.Sprintf("/repos/%s/git/refs/heads/%s", r, name)
+
+	body, err := json.Marshal(struct {
+		SHA   string `json:"sha"`
+		Force bool   `json:"force"`
+	}{
+		SHA:   sha,
+		Force: true,
+	})
+	if err != nil {
+		return Branch{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	if _, err := g.req(ctx, http.MethodPatch, path, bytes.NewReader(body), nil); err != nil {
+		return Branch{}, fmt.Errorf("%w: %w", ErrResetBranch, err)
+	}
+
+	return Branch{Name: name, Commit: Commit{SHA: sha}}, nil
+}
+
 func (g *GitHub) GetOrCreateBranch(ctx context.Context, r Repo, name, sha string) (Branch, error) {
 	b, err := g.GetBranch(ctx, r, name)
 	if err == nil {
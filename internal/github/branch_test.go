@@ -156,6 +156,82 @@ func TestDeleteBranch(t *testing.T) {
 	})
 }
 
+func TestUpdateBranch(t *testing.T) {
+	t.Parallel()
+
+	refPath := refAPIPath + "/heads/" + branch
+
+	t.Run("server error", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		_, err := g.UpdateBranch(t.Context(), repo, branch, sha)
+		if !errors.Is(err, ErrUpdateBranch) {
+			t.Fatalf("expected error %v, got %v", ErrUpdateBranch, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPatch, refPath, fmt.Appendf(nil, `{"sha":"%s"}`, sha))
+
+			w.WriteHeader(http.StatusOK)
+		})
+
+		got, err := g.UpdateBranch(t.Context(), repo, branch, sha)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.Name != branch || got.Commit.SHA != sha {
+			t.Fatalf("want '%v', but got %v", branch, got)
+		}
+	})
+}
+
+func TestResetBranch(t *testing.T) {
+	t.Parallel()
+
+	refPath := refAPIPath + "/heads/" + branch
+
+	t.Run("server error", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		_, err := g.ResetBranch(t.Context(), repo, branch, sha)
+		if !errors.Is(err, ErrResetBranch) {
+			t.Fatalf("expected error %v, got %v", ErrResetBranch, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPatch, refPath, fmt.Appendf(nil, `{"sha":"%s","force":true}`, sha))
+
+			w.WriteHeader(http.StatusOK)
+		})
+
+		got, err := g.ResetBranch(t.Context(), repo, branch, sha)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.Name != branch || got.Commit.SHA != sha {
+			t.Fatalf("want '%v', but got %v", branch, got)
+		}
+	})
+}
+
 func TestGetOrCreateBranch(t *testing.T) {
 	t.Parallel()
 
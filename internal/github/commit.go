@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	commitsPerPage = 100
+	shortSHALength = 7
+)
+
+var ErrGetCommits = errors.New("failed to get commits")
+
+// RepoCommit is a commit as listed in a repository's history.
+type RepoCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Author  User   `json:"author"`
+	Commit  struct {
+		Message string `json:"message"`
+	} `json:"commit"`
+}
+
+// ShortSHA returns the abbreviated SHA, as git shows it.
+func (c RepoCommit) ShortSHA() string {
+	if len(c.SHA) < shortSHALength {
+		return c.SHA
+	}
+
+	return c.SHA[:shortSHALength]
+}
+
+// Title returns the first line of the message.
+func (c RepoCommit) Title() string {
+	title, _, _ := strings.Cut(c.Commit.Message, "\n")
+
+	return title
+}
+
+// GetCommits lists at most limit commits that touched path, from the newest on
+// ref. An empty path lists all the commits, an empty ref the default branch.
+// https://docs.github.com/en/rest/commits/commits?apiVersion=2022-11-28#list-commits
+func (g *GitHub) GetCommits(ctx context.Context, r Repo, ref, path string, limit int) ([]RepoCommit, error) {
+	commits := []RepoCommit{}
+	perPage := min(limit, commitsPerPage)
+
+	for page := 1; len(commits) < limit; page++ {
+		q := url.Values{
+			"per_page": []string{strconv.Itoa(perPage)},
+			"page":     []string{strconv.Itoa(page)},
+		}
+
+		if path != "" {
+			q.Set("path", path)
+		}
+
+		if ref != "" {
+			q.Set("sha", ref)
+		}
+
+		pageCommits := []RepoCommit{}
+
+		apiPath := fmt.Sprintf("/repos/%s/commits?%s", r, q.Encode())
+		if _, err := g.req(ctx, http.MethodGet, apiPath, nil, &pageCommits); err != nil {
+			return nil, fmt.Errorf("%w for %s:%s@%s: %w", ErrGetCommits, r, path, ref, err)
+		}
+
+		commits = append(commits, pageCommits...)
+
+		if len(pageCommits) < perPage {
+			break
+		}
+	}
+
+	return commits[:min(len(commits), limit)], nil
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRepoCommit(t *testing.T) {
+	t.Parallel()
+
+	c := RepoCommit{SHA: "0123456789abcdef"}
+	c.Commit.Message = "title\n\nbody"
+
+	if got := c.ShortSHA(); got != "0123456" {
+		t.Fatalf("want short SHA '0123456', got %q", got)
+	}
+
+	if got := c.Title(); got != "title" {
+		t.Fatalf("want title 'title', got %q", got)
+	}
+}
+
+func TestGetCommits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.GetCommits(t.Context(), repo, branch, filePath, 1); !errors.Is(err, ErrGetCommits) {
+			t.Fatalf("expected error %v, got %v", ErrGetCommits, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/commits", nil)
+
+			q := r.URL.Query()
+			if q.Get("sha") != branch || q.Get("path") != filePath {
+				t.Fatalf("expected sha %q and path %q, got %v", branch, filePath, q)
+			}
+
+			fmt.Fprintf(w, `[{"sha": "%s", "commit": {"message": "message"}}]`, sha)
+		})
+
+		got, err := g.GetCommits(t.Context(), repo, branch, filePath, 10)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0].SHA != sha || got[0].Title() != "message" {
+			t.Fatalf("expected the commit, got %+v", got)
+		}
+	})
+
+	t.Run("stops at the limit", func(t *testing.T) {
+		t.Parallel()
+
+		requests := 0
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			if r.URL.Query().Has("sha") {
+				t.Fatal("expected no sha for an empty ref")
+			}
+
+			perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			fmt.Fprintf(w, "[%s{}]", strings.Repeat("{},", perPage-1))
+		})
+
+		got, err := g.GetCommits(t.Context(), repo, "", filePath, 150)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 150 || requests != 2 {
+			t.Fatalf("expected 150 commits in 2 requests, got %d in %d", len(got), requests)
+		}
+	})
+}
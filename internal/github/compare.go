@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+var ErrCompare = errors.New("failed to compare")
+
+const (
+	CompareIdentical = "identical"
+	CompareAhead     = "ahead"
+	CompareBehind    = "behind"
+	CompareDiverged  = "diverged"
+)
+
+type Comparison struct {
+	Status   string `json:"status"`
+	AheadBy  int    `json:"ahead_by"`
+	BehindBy int    `json:"behind_by"`
+}
+
+// Behind reports whether head is missing commits from base.
+func (c Comparison) Behind() bool {
+	return c.BehindBy > 0
+}
+
+// https://docs.github.com/en/rest/commits/commits?apiVersion=2022-11-28#compare-two-commits
+func (g *GitHub) Compare(ctx context.Context, r Repo, base, head string) (Comparison, error) {
+	// NOTE: Only the status is needed, the files and commits are skipped.
+	q := url.Values{"per_page": []string{"1"}}
+
+	path := fmt.Sprintf(
+		"/repos/%s/compare/%s...%s?%s",
+		r, url.PathEscape(base), url.PathEscape(head), q.Encode(),
+	)
+
+	c := Comparison{}
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &c); err != nil {
+		return Comparison{}, fmt.Errorf("%w %s...%s: %w", ErrCompare, base, head, err)
+	}
+
+	return c, nil
+}
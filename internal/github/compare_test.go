@@ -0,0 +1,49 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.Compare(t.Context(), repo, base, head); !errors.Is(err, ErrCompare) {
+			t.Fatalf("expected error %v, got %v", ErrCompare, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head), nil)
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"status":"diverged","ahead_by":1,"behind_by":2}`)
+		})
+
+		got, err := g.Compare(t.Context(), repo, base, head)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := Comparison{Status: CompareDiverged, AheadBy: 1, BehindBy: 2}
+		if got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
+		}
+
+		if !got.Behind() {
+			t.Fatal("expected head to be behind")
+		}
+	})
+}
@@ -1,13 +1,16 @@
 package github
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/log"
 )
 
 type File struct {
@@ -18,16 +21,33 @@ type File struct {
 	SHA     string `json:"sha"` // Blob hash.
 	HTMLURL string `json:"html_url"`
 
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+
 	// Content from the config
 	Repo   Repo   `json:"repo"`
 	Ref    string `json:"ref"`
 	Commit string `json:"commit"` // Commit hash.
+
+	// Vars are the template variables specific to this file.
+	Vars map[string]any `json:"vars,omitempty"`
 }
 
+const (
+	encodingBase64 = "base64"
+
+	// encodingNone is returned for files over 1 MB, whose content must be
+	// fetched from the blobs API.
+	// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#size-limits
+	encodingNone = "none"
+
+	// dirLimit is the most files the contents API lists in a directory.
+	dirLimit = 1000
+)
+
 var (
 	ErrGetFile     = errors.New("failed to get file")
 	ErrMissingFile = errors.New("file does not exist")
-	ErrUpdateFile  = errors.New("failed to create/update file")
 	ErrDecodeFile  = errors.New("failed to decode file")
 )
 
@@ -63,6 +83,22 @@ func (g *GitHub) GetFile(ctx context.Context, f *File) error {
 		return fmt.Errorf("%w: %w", ErrGetFile, err)
 	}
 
+	if f.Encoding == encodingNone {
+		log.Debug("File is too large for the contents API", "file", f, "size", f.Size)
+
+		content, err := g.GetBlob(ctx, f.Repo, f.SHA)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrGetFile, err)
+		}
+
+		f.Content = content
+		f.Encoding = encodingBase64
+
+		return nil
+	}
+
+	// NOTE: Decoding into a string is binary-safe, Go strings can hold any
+	// byte.
 	decoded, err := base64.StdEncoding.DecodeString(f.Content)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrDecodeFile, err)
@@ -73,42 +109,122 @@ func (g *GitHub) GetFile(ctx context.Context, f *File) error {
 	return nil
 }
 
-// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#create-or-update-file-contents
-func (g *GitHub) UpdateFile(ctx context.Context, f File, branch, message string) (File, error) {
-	body, err := json.Marshal(struct {
-		Message string `json:"message"`
-		Content string `json:"content"`
-		SHA     string `json:"sha"`
-		Branch  string `json:"branch"`
-	}{
-		Message: message,
-		Content: base64.StdEncoding.EncodeToString([]byte(f.Content)),
-		Branch:  branch,
-		SHA:     f.SHA,
-	})
+// dirEntry is an entry of a directory listing, which doesn't include the
+// content of its files.
+type dirEntry struct {
+	File
+
+	Type string `json:"type"`
+}
+
+// GetFileInfo gets the metadata of the file, e.g. its SHA, without its
+// content. It lists the parent directory, which is cached for the next files.
+// NOTE: The listing is limited to 1000 files, the file is fetched on its own
+// when the listing reaches it. So are the paths listed with another type than
+// a file or a directory, e.g. a symlink, which the contents API resolves.
+// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#get-repository-content
+func (g *GitHub) GetFileInfo(ctx context.Context, f *File) error {
+	entries, err := g.listDir(ctx, f.Repo, f.Ref, f.dir())
 	if err != nil {
-		return File{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+		return err
 	}
 
-	// NOTE: Non-trivial update.
-	// The response for this call will update the parameter file directly. It's
-	// fine because we want the new `SHA`. The `Name`, and `Path` won't change
-	// because we only update the content of the file. Also, since we're passing
-	// a file value (not pointer), the original file won't get changed; we are
-	// creating a new file. See tests.
-	out := struct {
-		File File `json:"content"`
-	}{File: f}
-
-	if _, err := g.req(
-		ctx,
-		http.MethodPut,
-		f.APIPath(),
-		bytes.NewReader(body),
-		&out,
-	); err != nil {
-		return File{}, fmt.Errorf("%w: %w", ErrUpdateFile, err)
+	fetch := len(entries) >= dirLimit
+
+	for _, e := range entries {
+		if e.Path != f.Path {
+			continue
+		}
+
+		switch e.Type {
+		case "file":
+			f.Name = e.Name
+			f.SHA = e.SHA
+			f.Size = e.Size
+			f.HTMLURL = e.HTMLURL
+			f.Content = ""
+
+			return nil
+
+		case "dir":
+			return fmt.Errorf("%w: %s", ErrMissingFile, f)
+
+		default:
+			log.Debug("Path is not a regular file", "file", f, "type", e.Type)
+
+			fetch = true
+		}
+	}
+
+	if !fetch {
+		return fmt.Errorf("%w: %s", ErrMissingFile, f)
+	}
+
+	if err := g.GetFile(ctx, f); err != nil {
+		return err
+	}
+
+	f.Content = ""
+
+	return nil
+}
+
+// listDir lists the directory, or returns its cached listing.
+func (g *GitHub) listDir(ctx context.Context, r Repo, ref, dir string) ([]dirEntry, error) {
+	key := fmt.Sprintf("%s@%s:%s", r, ref, dir)
+
+	g.mu.Lock()
+	entries, ok := g.dirs[key]
+	g.mu.Unlock()
+
+	if ok {
+		return entries, nil
+	}
+
+	q := url.Values{"ref": []string{ref}}
+	apiPath := fmt.Sprintf("/repos/%s/contents/%s?%s", r, dir, q.Encode())
+
+	status, err := g.req(ctx, http.MethodGet, apiPath, nil, &entries)
+	if err != nil {
+		if status == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %w", ErrMissingFile, err)
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrGetFile, err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.dirs == nil {
+		g.dirs = map[string][]dirEntry{}
+	}
+
+	g.dirs[key] = entries
+
+	return entries, nil
+}
+
+// forget drops the cached listings of the repo, once one of its branches
+// changed.
+func (g *GitHub) forget(r Repo) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	prefix := r.String() + "@"
+
+	for key := range g.dirs {
+		if strings.HasPrefix(key, prefix) {
+			delete(g.dirs, key)
+		}
+	}
+}
+
+func (f File) dir() string {
+	dir := path.Dir(f.Path)
+	if dir == "." {
+		return ""
 	}
 
-	return out.File, nil
+	return dir
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -68,64 +69,194 @@ func TestGetFile(t *testing.T) {
 			t.Fatalf("expected content to be 'ok' but got %s", f.Content)
 		}
 	})
+
+	t.Run("gets a large file from the blobs API", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case contentPath:
+				fmt.Fprintf(w, `{"content": "", "encoding": "none", "sha": "%s", "size": 2000000}`, sha)
+			case blobAPIPath + "/" + sha:
+				fmt.Fprintf(w, `{"content": "%s", "encoding": "base64"}`, base64Content)
+			default:
+				t.Fatalf("unexpected path %s", r.URL.Path)
+			}
+		})
+
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+		if err := g.GetFile(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Content != "ok" || f.SHA != sha || f.Size != 2000000 {
+			t.Fatalf("expected the large file, got %+v", f)
+		}
+	})
+
+	t.Run("keeps binary content", func(t *testing.T) {
+		t.Parallel()
+
+		binary := "\x89PNG\x00\xff"
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"content": "%s"}`, base64.StdEncoding.EncodeToString([]byte(binary)))
+		})
+
+		f := File{Repo: repo, Path: filePath}
+		if err := g.GetFile(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.Content != binary {
+			t.Fatalf("expected content to be %q but got %q", binary, f.Content)
+		}
+	})
 }
 
-func TestUpdateFile(t *testing.T) {
+func TestGetFileInfo(t *testing.T) {
 	t.Parallel()
 
-	f := File{
-		Repo:    repo,
-		Content: content,
-		SHA:     sha,
-		Path:    filePath,
-		Ref:     branch,
-	}
+	dirPath := "/repos/owner/repo/contents/path/to"
+
+	t.Run("fails to list the directory", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		f := File{Repo: repo, Path: filePath}
+		if err := g.GetFileInfo(t.Context(), &f); !errors.Is(err, ErrMissingFile) {
+			t.Fatalf("expected missing file error, got %v", err)
+		}
+	})
 
-	t.Run("fails", func(t *testing.T) {
+	t.Run("does not find the file", func(t *testing.T) {
 		t.Parallel()
 
 		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `[{"path": "%s", "type": "dir"}, {"path": "path/to/other", "type": "file"}]`, filePath)
 		})
 
-		_, err := g.UpdateFile(t.Context(), f, branch, message)
-		if err == nil {
-			t.Fatal("expected error, got nil")
+		f := File{Repo: repo, Path: filePath}
+		if err := g.GetFileInfo(t.Context(), &f); !errors.Is(err, ErrMissingFile) {
+			t.Fatalf("expected missing file error, got %v", err)
 		}
 	})
 
 	t.Run("succeeds", func(t *testing.T) {
 		t.Parallel()
 
-		const newSha = "newSha"
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, dirPath, nil)
+
+			if ref := r.URL.Query().Get("ref"); ref != branch {
+				t.Fatalf("expected ref to be '%s' but got '%s'", branch, ref)
+			}
+
+			fmt.Fprintf(w, `[{"name": "file", "path": "%s", "type": "file", "sha": "%s", "size": 2}]`, filePath, sha)
+		})
+
+		f := File{Repo: repo, Path: filePath, Ref: branch, Content: "stale"}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if f.SHA != sha || f.Size != 2 || f.Name != "file" || f.Content != "" {
+			t.Fatalf("expected the file info, got %+v", f)
+		}
+	})
+
+	t.Run("caches the listing until the branch changes", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				return
+			}
+
+			calls++
+
+			fmt.Fprintf(w, `[{"path": "%s", "type": "file", "sha": "%s"}, {"path": "path/to/other", "type": "file"}]`,
+				filePath, sha)
+		})
+
+		for _, p := range []string{filePath, "path/to/other"} {
+			f := File{Repo: repo, Path: p, Ref: branch}
+			if err := g.GetFileInfo(t.Context(), &f); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		if calls != 1 {
+			t.Fatalf("expected 1 listing, got %d", calls)
+		}
+
+		if _, err := g.UpdateBranch(t.Context(), repo, branch, sha); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if calls != 2 {
+			t.Fatalf("expected 2 listings, got %d", calls)
+		}
+	})
+
+	t.Run("gets the file when it is a symlink", func(t *testing.T) {
+		t.Parallel()
 
 		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
-			assertReq(t, r,
-				http.MethodPut,
-				contentPath,
-				fmt.Appendf(nil, `{"message":"%s","content":"%s","sha":"%s","branch":"%s"}`, message, base64Content, sha, branch),
-			)
-
-			w.WriteHeader(http.StatusCreated)
-			fmt.Fprintf(w, `{"content": {"sha":"%s"}}`, newSha)
+			if r.URL.Path == contentPath {
+				fmt.Fprintf(w, `{"name": "file", "path": "%s", "sha": "%s", "content": "%s"}`, filePath, sha, base64Content)
+
+				return
+			}
+
+			fmt.Fprintf(w, `[{"path": "%s", "type": "symlink"}, {"path": "path/to/other", "type": "file"}]`, filePath)
 		})
 
-		c, err := g.UpdateFile(t.Context(), f, branch, message)
-		if err != nil {
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
-		if c.SHA != newSha {
-			t.Fatalf("expected new sha to be '%s' but got '%s'", newSha, c.SHA)
+		if f.SHA != sha || f.Content != "" {
+			t.Fatalf("expected the file info, got %+v", f)
 		}
+	})
 
-		if c.Content != f.Content {
-			t.Fatalf("expected content to be '%s' but got '%s'", f.Content, c.Content)
+	t.Run("gets the file when the listing is full", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == contentPath {
+				fmt.Fprintf(w, `{"name": "file", "path": "%s", "sha": "%s", "content": "%s"}`, filePath, sha, base64Content)
+
+				return
+			}
+
+			entries := make([]string, dirLimit)
+			for i := range entries {
+				entries[i] = fmt.Sprintf(`{"path": "path/to/%d", "type": "file"}`, i)
+			}
+
+			fmt.Fprintf(w, "[%s]", strings.Join(entries, ","))
+		})
+
+		f := File{Repo: repo, Path: filePath, Ref: branch}
+		if err := g.GetFileInfo(t.Context(), &f); err != nil {
+			t.Fatalf("expected no error, got %v", err)
 		}
 
-		// Ensures that the old file stays unchanged.
-		if c.SHA == f.SHA {
-			t.Fatal("expected the original file not to change, but it did")
+		if f.SHA != sha || f.Content != "" {
+			t.Fatalf("expected the file info, got %+v", f)
 		}
 	})
 }
@@ -0,0 +1,162 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // Git's object IDs are SHA-1.
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+// ModeFile is the mode of a regular, non-executable, file.
+const ModeFile = "100644"
+
+var (
+	ErrGetBlob      = errors.New("failed to get blob")
+	ErrCreateBlob   = errors.New("failed to create blob")
+	ErrCreateTree   = errors.New("failed to create tree")
+	ErrCreateCommit = errors.New("failed to create commit")
+)
+
+// BlobSHA computes the SHA git gives to a blob with the content.
+// It matches the `sha` returned by the API for a file.
+func BlobSHA(content string) string {
+	h := sha1.New() //nolint:gosec // Git's object IDs are SHA-1.
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write([]byte(content))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GetBlob returns the decoded content of the blob, it supports files up to
+// 100 MB.
+// https://docs.github.com/en/rest/git/blobs?apiVersion=2022-11-28#get-a-blob
+func (g *GitHub) GetBlob(ctx context.Context, r Repo, sha string) (string, error) {
+	log.Debug("Get blob", "repo", r, "sha", sha)
+
+	path := fmt.Sprintf("/repos/%s/git/blobs/%s", r, sha)
+
+	out := struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}{}
+
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrGetBlob, err)
+	}
+
+	if out.Encoding != encodingBase64 {
+		return out.Content, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(out.Content)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecodeFile, err)
+	}
+
+	return string(decoded), nil
+}
+
+// https://docs.github.com/en/rest/git/blobs?apiVersion=2022-11-28#create-a-blob
+func (g *GitHub) CreateBlob(ctx context.Context, r Repo, content string) (string, error) {
+	body, err := json.Marshal(struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}{
+		Content:  base64.StdEncoding.EncodeToString([]byte(content)),
+		Encoding: encodingBase64,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/git/blobs", r)
+
+	out := struct {
+		SHA string `json:"sha"`
+	}{}
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), &out); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCreateBlob, err)
+	}
+
+	return out.SHA, nil
+}
+
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#create-a-tree
+// An entry without SHA deletes the file at its path.
+func (g *GitHub) CreateTree(ctx context.Context, r Repo, baseTree string, entries []TreeEntry) (Tree, error) {
+	log.Debug("Create tree", "repo", r, "base", baseTree, "entries", entries)
+
+	type entry struct {
+		Path string  `json:"path"`
+		Mode string  `json:"mode"`
+		Type string  `json:"type"`
+		SHA  *string `json:"sha"`
+	}
+
+	tree := make([]entry, 0, len(entries))
+
+	for _, e := range entries {
+		ne := entry{Path: e.Path, Mode: e.Mode, Type: e.Type}
+
+		if e.SHA != "" {
+			ne.SHA = &e.SHA
+		}
+
+		tree = append(tree, ne)
+	}
+
+	body, err := json.Marshal(struct {
+		BaseTree string  `json:"base_tree"`
+		Tree     []entry `json:"tree"`
+	}{
+		BaseTree: baseTree,
+		Tree:     tree,
+	})
+	if err != nil {
+		return Tree{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/git/trees", r)
+
+	t := Tree{}
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), &t); err != nil {
+		return Tree{}, fmt.Errorf("%w: %w", ErrCreateTree, err)
+	}
+
+	return t, nil
+}
+
+// https://docs.github.com/en/rest/git/commits?apiVersion=2022-11-28#create-a-commit
+func (g *GitHub) CreateCommit(ctx context.Context, r Repo, message, tree string, parents []string) (Commit, error) {
+	log.Debug("Create commit", "repo", r, "tree", tree, "parents", parents)
+
+	body, err := json.Marshal(struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}{
+		Message: message,
+		Tree:    tree,
+		Parents: parents,
+	})
+	if err != nil {
+		return Commit{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/git/commits", r)
+
+	c := Commit{}
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), &c); err != nil {
+		return Commit{}, fmt.Errorf("%w: %w", ErrCreateCommit, err)
+	}
+
+	return c, nil
+}
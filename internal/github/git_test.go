@@ -0,0 +1,202 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const (
+	blobAPIPath   = "/repos/owner/repo/git/blobs"
+	commitAPIPath = "/repos/owner/repo/git/commits"
+)
+
+func TestBlobSHA(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "", want: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		{content: "hello\n", want: "ce013625030ba8dba906f756967f9e9ca394464a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.content, func(t *testing.T) {
+			t.Parallel()
+
+			if got := BlobSHA(test.content); got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetBlob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.GetBlob(t.Context(), repo, sha); !errors.Is(err, ErrGetBlob) {
+			t.Fatalf("want error %v, got %v", ErrGetBlob, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, blobAPIPath+"/"+sha, nil)
+
+			fmt.Fprintf(w, `{"content": "%s", "encoding": "base64"}`, base64Content)
+		})
+
+		got, err := g.GetBlob(t.Context(), repo, sha)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if got != "ok" {
+			t.Fatalf("want 'ok', got %q", got)
+		}
+	})
+}
+
+func TestCreateBlob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if _, err := g.CreateBlob(t.Context(), repo, content); !errors.Is(err, ErrCreateBlob) {
+			t.Fatalf("expected error %v, got %v", ErrCreateBlob, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPost,
+				blobAPIPath,
+				fmt.Appendf(nil, `{"content":"%s","encoding":"base64"}`, base64Content),
+			)
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"sha": "%s"}`, sha)
+		})
+
+		got, err := g.CreateBlob(t.Context(), repo, content)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got != sha {
+			t.Fatalf("expected sha to be '%s' but got '%s'", sha, got)
+		}
+	})
+}
+
+func TestCreateTree(t *testing.T) {
+	t.Parallel()
+
+	entries := []TreeEntry{
+		{Path: "a", Mode: ModeFile, Type: TypeBlob, SHA: sha},
+		{Path: "b", Mode: ModeFile, Type: TypeBlob},
+	}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if _, err := g.CreateTree(t.Context(), repo, "base", entries); !errors.Is(err, ErrCreateTree) {
+			t.Fatalf("expected error %v, got %v", ErrCreateTree, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPost,
+				"/repos/owner/repo/git/trees",
+				fmt.Appendf(nil,
+					`{"base_tree":"base","tree":[`+
+						`{"path":"a","mode":"100644","type":"blob","sha":"%s"},`+
+						`{"path":"b","mode":"100644","type":"blob","sha":null}`+
+						`]}`,
+					sha,
+				),
+			)
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"sha": "tree"}`)
+		})
+
+		got, err := g.CreateTree(t.Context(), repo, "base", entries)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.SHA != "tree" {
+			t.Fatalf("expected sha to be 'tree' but got '%s'", got.SHA)
+		}
+	})
+}
+
+func TestCreateCommit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		_, err := g.CreateCommit(t.Context(), repo, message, "tree", []string{sha})
+		if !errors.Is(err, ErrCreateCommit) {
+			t.Fatalf("expected error %v, got %v", ErrCreateCommit, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPost,
+				commitAPIPath,
+				fmt.Appendf(nil, `{"message":"%s","tree":"tree","parents":["%s"]}`, message, sha),
+			)
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"sha": "commit"}`)
+		})
+
+		got, err := g.CreateCommit(t.Context(), repo, message, "tree", []string{sha})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.SHA != "commit" {
+			t.Fatalf("expected sha to be 'commit' but got '%s'", got.SHA)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/nobe4/action-ln/internal/client"
 	"github.com/nobe4/action-ln/internal/log"
@@ -20,11 +21,19 @@ import (
 
 type Getter interface {
 	GetFile(ctx context.Context, f *File) error
+	GetFileInfo(ctx context.Context, f *File) error
 	GetRepo(ctx context.Context, r *Repo) error
+	GetTree(ctx context.Context, r Repo, ref string) (Tree, error)
+	GetTags(ctx context.Context, r Repo) ([]Tag, error)
+	GetLatestRelease(ctx context.Context, r Repo) (Release, error)
+	GetCommits(ctx context.Context, r Repo, ref, path string, limit int) ([]RepoCommit, error)
 }
 
 type Updater interface {
-	UpdateFile(ctx context.Context, f File, head string, msg string) (File, error)
+	CreateBlob(ctx context.Context, r Repo, content string) (string, error)
+	CreateTree(ctx context.Context, r Repo, baseTree string, entries []TreeEntry) (Tree, error)
+	CreateCommit(ctx context.Context, r Repo, msg, tree string, parents []string) (Commit, error)
+	UpdateBranch(ctx context.Context, r Repo, name, sha string) (Branch, error)
 }
 
 type GetterUpdater interface {
@@ -45,6 +54,10 @@ type GitHub struct {
 	client   client.Doer
 	Token    string
 	endpoint string
+
+	mu sync.Mutex
+	// dirs caches the directory listings, see GitHub.GetFileInfo.
+	dirs map[string][]dirEntry
 }
 
 func New(c client.Doer, endpoint string) *GitHub {
@@ -59,8 +72,10 @@ type User struct {
 }
 
 func (g *GitHub) req(ctx context.Context, method, path string, body io.Reader, out any) (int, error) {
-	url := g.endpoint + path
+	return g.reqURL(ctx, method, g.endpoint+path, body, out)
+}
 
+func (g *GitHub) reqURL(ctx context.Context, method, url string, body io.Reader, out any) (int, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		log.Debug("Request", "method", method, "url", url, "status", "failed to create", "err", err)
@@ -0,0 +1,65 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var ErrGraphQL = errors.New("graphql request failed")
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// graphQLURL returns the GraphQL endpoint matching the REST one.
+// E.g. https://api.github.com => https://api.github.com/graphql
+// E.g. https://ghe.example.com/api/v3 => https://ghe.example.com/api/graphql
+func (g *GitHub) graphQLURL() string {
+	return strings.TrimSuffix(g.endpoint, "/v3") + "/graphql"
+}
+
+// https://docs.github.com/en/graphql/guides/forming-calls-with-graphql
+func (g *GitHub) graphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	res := struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}{}
+
+	if _, err := g.reqURL(ctx, http.MethodPost, g.graphQLURL(), bytes.NewReader(body), &res); err != nil {
+		return fmt.Errorf("%w: %w", ErrGraphQL, err)
+	}
+
+	// NOTE: GraphQL reports errors with a 200 status.
+	if len(res.Errors) > 0 {
+		messages := make([]string, 0, len(res.Errors))
+		for _, e := range res.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return fmt.Errorf("%w: %s", ErrGraphQL, strings.Join(messages, ", "))
+	}
+
+	if out != nil && len(res.Data) > 0 {
+		if err := json.Unmarshal(res.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
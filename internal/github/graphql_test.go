@@ -0,0 +1,27 @@
+package github
+
+import "testing"
+
+func TestGraphQLURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "https://api.github.com", want: "https://api.github.com/graphql"},
+		{endpoint: "https://ghe.example.com/api/v3", want: "https://ghe.example.com/api/graphql"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			t.Parallel()
+
+			g := New(nil, test.endpoint)
+
+			if got := g.graphQLURL(); got != test.want {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// NOTE: Pull requests are issues, the issues API applies to them as well.
+// https://docs.github.com/en/rest/issues?apiVersion=2022-11-28
+
+var (
+	ErrAddLabels    = errors.New("failed to add labels")
+	ErrAddAssignees = errors.New("failed to add assignees")
+	ErrSetMilestone = errors.New("failed to set milestone")
+	ErrAddComment   = errors.New("failed to add comment")
+)
+
+func (p Pull) issueAPIPath() string {
+	return fmt.Sprintf("/repos/%s/issues/%d", p.Repo, p.Number)
+}
+
+// https://docs.github.com/en/rest/issues/labels?apiVersion=2022-11-28#add-labels-to-an-issue
+func (g *GitHub) AddLabels(ctx context.Context, p Pull, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Labels []string `json:"labels"`
+	}{
+		Labels: labels,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := p.issueAPIPath() + "/labels"
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddLabels, err)
+	}
+
+	return nil
+}
+
+// https://docs.github.com/en/rest/issues/assignees?apiVersion=2022-11-28#add-assignees-to-an-issue
+func (g *GitHub) AddAssignees(ctx context.Context, p Pull, assignees []string) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Assignees []string `json:"assignees"`
+	}{
+		Assignees: assignees,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := p.issueAPIPath() + "/assignees"
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddAssignees, err)
+	}
+
+	return nil
+}
+
+// https://docs.github.com/en/rest/issues/issues?apiVersion=2022-11-28#update-an-issue
+func (g *GitHub) SetMilestone(ctx context.Context, p Pull, milestone int) error {
+	if milestone == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Milestone int `json:"milestone"`
+	}{
+		Milestone: milestone,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	if _, err := g.req(ctx, http.MethodPatch, p.issueAPIPath(), bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrSetMilestone, err)
+	}
+
+	return nil
+}
+
+// https://docs.github.com/en/rest/issues/comments?apiVersion=2022-11-28#create-an-issue-comment
+func (g *GitHub) AddComment(ctx context.Context, p Pull, comment string) error {
+	body, err := json.Marshal(struct {
+		Body string `json:"body"`
+	}{
+		Body: comment,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := p.issueAPIPath() + "/comments"
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddComment, err)
+	}
+
+	return nil
+}
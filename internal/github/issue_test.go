@@ -0,0 +1,174 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const issueAPIPath = "/repos/owner/repo/issues/123"
+
+func TestAddLabels(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("does nothing without labels", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Fatal("expected no request")
+		})
+
+		if err := g.AddLabels(t.Context(), pull, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if err := g.AddLabels(t.Context(), pull, []string{"a"}); !errors.Is(err, ErrAddLabels) {
+			t.Fatalf("expected error %v, got %v", ErrAddLabels, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, issueAPIPath+"/labels", []byte(`{"labels":["a","b"]}`))
+
+			w.WriteHeader(http.StatusOK)
+		})
+
+		if err := g.AddLabels(t.Context(), pull, []string{"a", "b"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestAddAssignees(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("does nothing without assignees", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Fatal("expected no request")
+		})
+
+		if err := g.AddAssignees(t.Context(), pull, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if err := g.AddAssignees(t.Context(), pull, []string{"a"}); !errors.Is(err, ErrAddAssignees) {
+			t.Fatalf("expected error %v, got %v", ErrAddAssignees, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, issueAPIPath+"/assignees", []byte(`{"assignees":["a"]}`))
+
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		if err := g.AddAssignees(t.Context(), pull, []string{"a"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestSetMilestone(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("does nothing without milestone", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Fatal("expected no request")
+		})
+
+		if err := g.SetMilestone(t.Context(), pull, 0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if err := g.SetMilestone(t.Context(), pull, 1); !errors.Is(err, ErrSetMilestone) {
+			t.Fatalf("expected error %v, got %v", ErrSetMilestone, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPatch, issueAPIPath, fmt.Appendf(nil, `{"milestone":%d}`, 2))
+
+			w.WriteHeader(http.StatusOK)
+		})
+
+		if err := g.SetMilestone(t.Context(), pull, 2); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestAddComment(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		if err := g.AddComment(t.Context(), pull, "comment"); !errors.Is(err, ErrAddComment) {
+			t.Fatalf("expected error %v, got %v", ErrAddComment, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, issueAPIPath+"/comments", []byte(`{"body":"comment"}`))
+
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		if err := g.AddComment(t.Context(), pull, "comment"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
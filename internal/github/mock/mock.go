@@ -8,39 +8,92 @@ import (
 
 type Getter struct {
 	FileHandler func(*github.File) error
+	// InfoHandler defaults to FileHandler.
+	InfoHandler func(*github.File) error
 	RepoHandler func(*github.Repo) error
+	TreeHandler func(github.Repo, string) (github.Tree, error)
+	TagsHandler func(github.Repo) ([]github.Tag, error)
+	// ReleaseHandler returns the latest release.
+	ReleaseHandler func(github.Repo) (github.Release, error)
+	// CommitsHandler defaults to no commits.
+	CommitsHandler func(github.Repo, string, string, int) ([]github.RepoCommit, error)
 }
 
 func (g Getter) GetFile(_ context.Context, f *github.File) error {
 	return g.FileHandler(f)
 }
 
+func (g Getter) GetFileInfo(_ context.Context, f *github.File) error {
+	if g.InfoHandler == nil {
+		return g.FileHandler(f)
+	}
+
+	return g.InfoHandler(f)
+}
+
 func (g Getter) GetRepo(_ context.Context, r *github.Repo) error {
 	return g.RepoHandler(r)
 }
 
+func (g Getter) GetTree(_ context.Context, r github.Repo, ref string) (github.Tree, error) {
+	return g.TreeHandler(r, ref)
+}
+
+func (g Getter) GetTags(_ context.Context, r github.Repo) ([]github.Tag, error) {
+	return g.TagsHandler(r)
+}
+
+func (g Getter) GetLatestRelease(_ context.Context, r github.Repo) (github.Release, error) {
+	return g.ReleaseHandler(r)
+}
+
+func (g Getter) GetCommits(
+	_ context.Context,
+	r github.Repo,
+	ref, path string,
+	limit int,
+) ([]github.RepoCommit, error) {
+	if g.CommitsHandler == nil {
+		return []github.RepoCommit{}, nil
+	}
+
+	return g.CommitsHandler(r, ref, path, limit)
+}
+
 type Updater struct {
-	Handler func(github.File, string, string) (github.File, error)
+	BlobHandler   func(github.Repo, string) (string, error)
+	TreeHandler   func(github.Repo, string, []github.TreeEntry) (github.Tree, error)
+	CommitHandler func(github.Repo, string, string, []string) (github.Commit, error)
+	BranchHandler func(github.Repo, string, string) (github.Branch, error)
 }
 
-func (g Updater) UpdateFile(_ context.Context, f github.File, head, msg string) (github.File, error) {
-	return g.Handler(f, head, msg)
+func (g Updater) CreateBlob(_ context.Context, r github.Repo, content string) (string, error) {
+	return g.BlobHandler(r, content)
 }
 
-type GetterUpdater struct {
-	GetFileHandler func(*github.File) error
-	GetRepoHandler func(*github.Repo) error
-	UpdateHandler  func(github.File, string, string) (github.File, error)
+func (g Updater) CreateTree(
+	_ context.Context,
+	r github.Repo,
+	baseTree string,
+	entries []github.TreeEntry,
+) (github.Tree, error) {
+	return g.TreeHandler(r, baseTree, entries)
 }
 
-func (g GetterUpdater) GetFile(_ context.Context, f *github.File) error {
-	return g.GetFileHandler(f)
+func (g Updater) CreateCommit(
+	_ context.Context,
+	r github.Repo,
+	msg, tree string,
+	parents []string,
+) (github.Commit, error) {
+	return g.CommitHandler(r, msg, tree, parents)
 }
 
-func (g GetterUpdater) GetRepo(_ context.Context, r *github.Repo) error {
-	return g.GetRepoHandler(r)
+func (g Updater) UpdateBranch(_ context.Context, r github.Repo, name, sha string) (github.Branch, error) {
+	return g.BranchHandler(r, name, sha)
 }
 
-func (g GetterUpdater) UpdateFile(_ context.Context, f github.File, head, msg string) (github.File, error) {
-	return g.UpdateHandler(f, head, msg)
+type GetterUpdater struct {
+	Getter
+	Updater
 }
@@ -8,6 +8,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	reviewsPerPage = 100
+	methodSquash   = "squash"
 )
 
 var (
@@ -15,16 +23,42 @@ var (
 	ErrGetPull    = errors.New("failed to get pull")
 	ErrCreatePull = errors.New("failed to create pull")
 	ErrPullExists = errors.New("pull already exist")
+
+	ErrUpdatePull       = errors.New("failed to update pull")
+	ErrConvertToDraft   = errors.New("failed to convert pull to draft")
+	ErrRequestReviewers = errors.New("failed to request reviewers")
+
+	ErrMergePull        = errors.New("failed to merge pull")
+	ErrPullNotMergeable = errors.New("pull is not mergeable")
+	ErrEnableAutoMerge  = errors.New("failed to enable auto-merge")
+	ErrClosePull        = errors.New("failed to close pull")
 )
 
 type Pull struct {
-	Number int `json:"number"`
+	Number  int    `json:"number"`
+	NodeID  string `json:"node_id"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Draft   bool   `json:"draft"`
+
+	Head struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+
+	// AutoMerge is set when auto-merge is enabled on the pull.
+	AutoMerge *struct {
+		MergeMethod string `json:"merge_method"`
+	} `json:"auto_merge"`
 
 	Repo Repo
 	New  bool
 }
 
 func (p Pull) String() string {
+	if p.HTMLURL != "" {
+		return p.HTMLURL
+	}
+
 	return fmt.Sprintf("https://github.com/%s/pull/%d", p.Repo, p.Number)
 }
 
@@ -63,17 +97,24 @@ func (g *GitHub) GetPull(ctx context.Context, repo Repo, base, head string) (Pul
 }
 
 // https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#create-a-pull-request
-func (g *GitHub) CreatePull(ctx context.Context, repo Repo, base, head, title, pullBody string) (Pull, error) {
+func (g *GitHub) CreatePull(
+	ctx context.Context,
+	repo Repo,
+	base, head, title, pullBody string,
+	draft bool,
+) (Pull, error) {
 	body, err := json.Marshal(struct {
 		Title string `json:"title"`
 		Head  string `json:"head"`
 		Base  string `json:"base"`
 		Body  string `json:"body"`
+		Draft bool   `json:"draft"`
 	}{
 		Title: title,
 		Body:  pullBody,
 		Head:  repo.Owner.Login + ":" + head,
 		Base:  base,
+		Draft: draft,
 	})
 	if err != nil {
 		return Pull{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
@@ -93,7 +134,47 @@ func (g *GitHub) CreatePull(ctx context.Context, repo Repo, base, head, title, p
 	return pull, nil
 }
 
-func (g *GitHub) GetOrCreatePull(ctx context.Context, repo Repo, base, head, title, body string) (Pull, error) {
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#update-a-pull-request
+func (g *GitHub) UpdatePull(ctx context.Context, p Pull, title, pullBody string) (Pull, error) {
+	body, err := json.Marshal(struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}{
+		Title: title,
+		Body:  pullBody,
+	})
+	if err != nil {
+		return Pull{}, fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d", p.Repo, p.Number)
+
+	if _, err := g.req(ctx, http.MethodPatch, path, bytes.NewReader(body), &p); err != nil {
+		return Pull{}, fmt.Errorf("%w: %w", ErrUpdatePull, err)
+	}
+
+	return p, nil
+}
+
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#update-a-pull-request
+func (g *GitHub) ClosePull(ctx context.Context, p Pull) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d", p.Repo, p.Number)
+
+	body := []byte(`{"state":"closed"}`)
+
+	if _, err := g.req(ctx, http.MethodPatch, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrClosePull, err)
+	}
+
+	return nil
+}
+
+func (g *GitHub) GetOrCreatePull(
+	ctx context.Context,
+	repo Repo,
+	base, head, title, body string,
+	draft bool,
+) (Pull, error) {
 	p, err := g.GetPull(ctx, repo, base, head)
 	if err == nil {
 		return p, nil
@@ -103,5 +184,228 @@ func (g *GitHub) GetOrCreatePull(ctx context.Context, repo Repo, base, head, tit
 		return Pull{}, err
 	}
 
-	return g.CreatePull(ctx, repo, base, head, title, body)
+	return g.CreatePull(ctx, repo, base, head, title, body, draft)
+}
+
+// ConvertToDraft converts an open pull to a draft.
+// The REST API doesn't support it, so it goes through GraphQL.
+// https://docs.github.com/en/graphql/reference/mutations#convertpullrequesttodraft
+func (g *GitHub) ConvertToDraft(ctx context.Context, p Pull) error {
+	const query = `mutation($id: ID!) {
+  convertPullRequestToDraft(input: {pullRequestId: $id}) {
+    clientMutationId
+  }
+}`
+
+	if err := g.graphQL(ctx, query, map[string]any{"id": p.NodeID}, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrConvertToDraft, err)
+	}
+
+	return nil
+}
+
+// RequestReviewers only requests the reviewers and teams that were never
+// requested, and the reviewers that haven't reviewed yet, so that they are not
+// notified again on every run.
+// https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#request-reviewers-for-a-pull-request
+func (g *GitHub) RequestReviewers(ctx context.Context, p Pull, reviewers, teams []string) error {
+	if len(reviewers) == 0 && len(teams) == 0 {
+		return nil
+	}
+
+	// NOTE: A new pull has no reviewers yet.
+	if !p.New {
+		users, knownTeams, err := g.getReviewers(ctx, p)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrRequestReviewers, err)
+		}
+
+		reviewers = missing(reviewers, users)
+		teams = missing(teams, knownTeams)
+
+		if len(reviewers) == 0 && len(teams) == 0 {
+			return nil
+		}
+	}
+
+	body, err := json.Marshal(struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{
+		Reviewers:     reviewers,
+		TeamReviewers: teams,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", p.Repo, p.Number)
+
+	if _, err := g.req(ctx, http.MethodPost, path, bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestReviewers, err)
+	}
+
+	return nil
+}
+
+// getReviewers returns the users and teams that were ever requested, and the
+// users that reviewed the pull.
+// NOTE: The requests are read from the events, as GitHub removes a request
+// once it's fulfilled.
+// https://docs.github.com/en/rest/issues/events?apiVersion=2022-11-28#list-issue-events
+// https://docs.github.com/en/rest/pulls/reviews?apiVersion=2022-11-28#list-reviews-for-a-pull-request
+func (g *GitHub) getReviewers(ctx context.Context, p Pull) (users, teams []string, err error) {
+	for page := 1; ; page++ {
+		events := []struct {
+			Event             string `json:"event"`
+			RequestedReviewer User   `json:"requested_reviewer"`
+			RequestedTeam     struct {
+				Slug string `json:"slug"`
+			} `json:"requested_team"`
+		}{}
+
+		path := fmt.Sprintf("/repos/%s/issues/%d/events?%s", p.Repo, p.Number, pageQuery(page))
+		if _, err := g.req(ctx, http.MethodGet, path, nil, &events); err != nil {
+			return nil, nil, err
+		}
+
+		for _, e := range events {
+			if e.Event != "review_requested" {
+				continue
+			}
+
+			users = append(users, e.RequestedReviewer.Login)
+			teams = append(teams, e.RequestedTeam.Slug)
+		}
+
+		if len(events) < reviewsPerPage {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		reviews := []struct {
+			User User `json:"user"`
+		}{}
+
+		path := fmt.Sprintf("/repos/%s/pulls/%d/reviews?%s", p.Repo, p.Number, pageQuery(page))
+		if _, err := g.req(ctx, http.MethodGet, path, nil, &reviews); err != nil {
+			return nil, nil, err
+		}
+
+		for _, r := range reviews {
+			users = append(users, r.User.Login)
+		}
+
+		if len(reviews) < reviewsPerPage {
+			return users, teams, nil
+		}
+	}
+}
+
+func pageQuery(page int) string {
+	return url.Values{
+		"per_page": []string{strconv.Itoa(reviewsPerPage)},
+		"page":     []string{strconv.Itoa(page)},
+	}.Encode()
+}
+
+// missing returns the names that are not in known, logins are not case
+// sensitive.
+func missing(names, known []string) []string {
+	out := []string{}
+
+	for _, n := range names {
+		if !slices.ContainsFunc(known, func(k string) bool { return strings.EqualFold(n, k) }) {
+			out = append(out, n)
+		}
+	}
+
+	return out
+}
+
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#merge-a-pull-request
+func (g *GitHub) MergePull(ctx context.Context, p Pull, method string) error {
+	title, message, err := g.squashMessage(ctx, p, method)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMergePull, err)
+	}
+
+	g.forget(p.Repo)
+
+	body, err := json.Marshal(struct {
+		MergeMethod   string `json:"merge_method"`
+		SHA           string `json:"sha,omitempty"`
+		CommitTitle   string `json:"commit_title,omitempty"`
+		CommitMessage string `json:"commit_message,omitempty"`
+	}{
+		MergeMethod:   method,
+		SHA:           p.Head.SHA,
+		CommitTitle:   title,
+		CommitMessage: message,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMarshalRequest, err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d/merge", p.Repo, p.Number)
+
+	if status, err := g.req(ctx, http.MethodPut, path, bytes.NewReader(body), nil); err != nil {
+		// NOTE: 405 is returned when the checks or reviews block the merge.
+		if status == http.StatusMethodNotAllowed {
+			return fmt.Errorf("%w: %w", ErrPullNotMergeable, err)
+		}
+
+		return fmt.Errorf("%w: %w", ErrMergePull, err)
+	}
+
+	return nil
+}
+
+// squashMessage returns the title and message of the squashed commit, which
+// keep the head commit's message and its trailers. GitHub's default message
+// may drop them, depending on the repository settings.
+// Other methods keep the head commit, and return empty strings.
+func (g *GitHub) squashMessage(ctx context.Context, p Pull, method string) (string, string, error) {
+	if method != methodSquash || p.Head.SHA == "" {
+		return "", "", nil
+	}
+
+	commits, err := g.GetCommits(ctx, p.Repo, p.Head.SHA, "", 1)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(commits) == 0 {
+		return "", "", nil
+	}
+
+	title := ""
+	if p.Title != "" {
+		title = fmt.Sprintf("%s (#%d)", p.Title, p.Number)
+	}
+
+	return title, commits[0].Commit.Message, nil
+}
+
+// EnableAutoMerge merges the pull once all its requirements are met.
+// The REST API doesn't support it, so it goes through GraphQL.
+// https://docs.github.com/en/graphql/reference/mutations#enablepullrequestautomerge
+func (g *GitHub) EnableAutoMerge(ctx context.Context, p Pull, method string) error {
+	const query = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) {
+    clientMutationId
+  }
+}`
+
+	variables := map[string]any{
+		"id":     p.NodeID,
+		"method": strings.ToUpper(method),
+	}
+
+	if err := g.graphQL(ctx, query, variables, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrEnableAutoMerge, err)
+	}
+
+	return nil
 }
@@ -1,9 +1,11 @@
 package github
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -77,7 +79,7 @@ func TestCreatePull(t *testing.T) {
 			w.WriteHeader(http.StatusUnprocessableEntity)
 		})
 
-		_, err := g.CreatePull(t.Context(), repo, "base", "head", "title", "body")
+		_, err := g.CreatePull(t.Context(), repo, "base", "head", "title", "body", false)
 		if !errors.Is(err, ErrPullExists) {
 			t.Fatalf("expected error %q, got %q", ErrPullExists, err)
 		}
@@ -90,14 +92,14 @@ func TestCreatePull(t *testing.T) {
 			assertReq(t, r,
 				http.MethodPost,
 				pullAPIPath,
-				fmt.Appendf(nil, `{"title":"%s","head":"%s:%s","base":"%s","body":"%s"}`, title, repo.Owner.Login, head, base, body),
+				fmt.Appendf(nil, `{"title":"%s","head":"%s:%s","base":"%s","body":"%s","draft":true}`, title, repo.Owner.Login, head, base, body),
 			)
 
 			w.WriteHeader(http.StatusCreated)
 			fmt.Fprintf(w, `{"number": %d}\n`, number)
 		})
 
-		got, err := g.CreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.CreatePull(t.Context(), repo, base, head, title, body, true)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -116,6 +118,48 @@ func TestCreatePull(t *testing.T) {
 	})
 }
 
+func TestUpdatePull(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		if _, err := g.UpdatePull(t.Context(), pull, title, body); !errors.Is(err, ErrUpdatePull) {
+			t.Fatalf("expected error %v, got %v", ErrUpdatePull, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPatch,
+				fmt.Sprintf("%s/%d", pullAPIPath, number),
+				fmt.Appendf(nil, `{"title":"%s","body":"%s"}`, title, body),
+			)
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"number": %d}`, number)
+		})
+
+		got, err := g.UpdatePull(t.Context(), pull, title, body)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.Number != number || got.Repo != repo {
+			t.Fatalf("expected pull %v, got %v", pull, got)
+		}
+	})
+}
+
 func TestGetOrCreatePull(t *testing.T) {
 	t.Parallel()
 
@@ -131,7 +175,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			fmt.Fprintf(w, `[{"number": %d}]\n`, number)
 		})
 
-		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -152,7 +196,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			w.WriteHeader(http.StatusInternalServerError)
 		})
 
-		_, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		_, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err == nil {
 			t.Fatalf("expected error, got nil")
 		}
@@ -179,7 +223,7 @@ func TestGetOrCreatePull(t *testing.T) {
 			i++
 		})
 
-		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body)
+		got, err := g.GetOrCreatePull(t.Context(), repo, base, head, title, body, false)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -193,3 +237,321 @@ func TestGetOrCreatePull(t *testing.T) {
 		}
 	})
 }
+
+func TestRequestReviewers(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+	path := fmt.Sprintf("%s/%d/requested_reviewers", pullAPIPath, number)
+
+	t.Run("does nothing without reviewers", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Fatal("expected no request")
+		})
+
+		if err := g.RequestReviewers(t.Context(), pull, nil, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		err := g.RequestReviewers(t.Context(), pull, []string{"a"}, nil)
+		if !errors.Is(err, ErrRequestReviewers) {
+			t.Fatalf("expected error %v, got %v", ErrRequestReviewers, err)
+		}
+	})
+
+	t.Run("requests all the reviewers of a new pull", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, path, []byte(`{"reviewers":["a","b"],"team_reviewers":["c"]}`))
+
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		p := Pull{Repo: repo, Number: number, New: true}
+		if err := g.RequestReviewers(t.Context(), p, []string{"a", "b"}, []string{"c"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("only requests the missing reviewers", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case fmt.Sprintf("/repos/owner/repo/issues/%d/events", number):
+				fmt.Fprint(w, `[
+					{"event": "review_requested", "requested_reviewer": {"login": "A"}},
+					{"event": "review_requested", "requested_team": {"slug": "c"}},
+					{"event": "labeled"}
+				]`)
+
+			case fmt.Sprintf("%s/%d/reviews", pullAPIPath, number):
+				fmt.Fprint(w, `[{"user": {"login": "b"}}]`)
+
+			default:
+				assertReq(t, r, http.MethodPost, path, []byte(`{"reviewers":["d"],"team_reviewers":["e"]}`))
+
+				w.WriteHeader(http.StatusCreated)
+			}
+		})
+
+		err := g.RequestReviewers(t.Context(), pull, []string{"a", "b", "d"}, []string{"c", "e"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("does nothing when all are known", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Fatalf("expected no request, got %s %s", r.Method, r.URL.Path)
+			}
+
+			fmt.Fprint(w, `[{"event": "review_requested", "requested_reviewer": {"login": "a"}}]`)
+		})
+
+		if err := g.RequestReviewers(t.Context(), pull, []string{"a"}, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestConvertToDraft(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number, NodeID: "PR_id"}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"errors":[{"message":"not allowed"}]}`)
+		})
+
+		if err := g.ConvertToDraft(t.Context(), pull); !errors.Is(err, ErrConvertToDraft) {
+			t.Fatalf("expected error %v, got %v", ErrConvertToDraft, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, "/graphql", nil)
+
+			req := struct {
+				Query     string            `json:"query"`
+				Variables map[string]string `json:"variables"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode the request: %v", err)
+			}
+
+			if req.Variables["id"] != "PR_id" || !strings.Contains(req.Query, "convertPullRequestToDraft") {
+				t.Fatalf("unexpected request %v", req)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"data":{}}`)
+		})
+
+		if err := g.ConvertToDraft(t.Context(), pull); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestMergePull(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("is not mergeable", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		})
+
+		if err := g.MergePull(t.Context(), pull, "squash"); !errors.Is(err, ErrPullNotMergeable) {
+			t.Fatalf("expected error %v, got %v", ErrPullNotMergeable, err)
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		})
+
+		if err := g.MergePull(t.Context(), pull, "squash"); !errors.Is(err, ErrMergePull) {
+			t.Fatalf("expected error %v, got %v", ErrMergePull, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPut,
+				fmt.Sprintf("%s/%d/merge", pullAPIPath, number),
+				[]byte(`{"merge_method":"squash"}`),
+			)
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"merged":true}`)
+		})
+
+		if err := g.MergePull(t.Context(), pull, "squash"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("keeps the head commit trailers when squashing", func(t *testing.T) {
+		t.Parallel()
+
+		message := "auto(ln): update links\n\nSynced-From: owner/repo:file@abc\n"
+
+		p := pull
+		p.Title = "auto(ln): update links"
+		p.Head.SHA = head
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				assertReq(t, r, http.MethodGet, "/repos/owner/repo/commits", nil)
+
+				if sha := r.URL.Query().Get("sha"); sha != head {
+					t.Fatalf("want sha %q, got %q", head, sha)
+				}
+
+				fmt.Fprintf(w, `[{"sha":%q,"commit":{"message":%q}}]`, head, message)
+
+				return
+			}
+
+			got := struct {
+				SHA           string `json:"sha"`
+				CommitTitle   string `json:"commit_title"`
+				CommitMessage string `json:"commit_message"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode the request: %v", err)
+			}
+
+			if got.SHA != head {
+				t.Fatalf("want sha %q, got %q", head, got.SHA)
+			}
+
+			if want := fmt.Sprintf("auto(ln): update links (#%d)", number); got.CommitTitle != want {
+				t.Fatalf("want commit title %q, got %q", want, got.CommitTitle)
+			}
+
+			if !strings.Contains(got.CommitMessage, "Synced-From: owner/repo:file@abc") {
+				t.Fatalf("want the trailers in the commit message, got %q", got.CommitMessage)
+			}
+
+			fmt.Fprint(w, `{"merged":true}`)
+		})
+
+		if err := g.MergePull(t.Context(), p, "squash"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestEnableAutoMerge(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number, NodeID: "PR_id"}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"errors":[{"message":"auto-merge is not allowed"}]}`)
+		})
+
+		if err := g.EnableAutoMerge(t.Context(), pull, "squash"); !errors.Is(err, ErrEnableAutoMerge) {
+			t.Fatalf("expected error %v, got %v", ErrEnableAutoMerge, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodPost, "/graphql", nil)
+
+			req := struct {
+				Variables map[string]string `json:"variables"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode the request: %v", err)
+			}
+
+			if req.Variables["id"] != "PR_id" || req.Variables["method"] != "SQUASH" {
+				t.Fatalf("unexpected variables %v", req.Variables)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"data":{}}`)
+		})
+
+		if err := g.EnableAutoMerge(t.Context(), pull, "squash"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestClosePull(t *testing.T) {
+	t.Parallel()
+
+	pull := Pull{Repo: repo, Number: number}
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		})
+
+		if err := g.ClosePull(t.Context(), pull); !errors.Is(err, ErrClosePull) {
+			t.Fatalf("expected error %v, got %v", ErrClosePull, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r,
+				http.MethodPatch,
+				fmt.Sprintf("%s/%d", pullAPIPath, number),
+				[]byte(`{"state":"closed"}`),
+			)
+
+			w.WriteHeader(http.StatusOK)
+		})
+
+		if err := g.ClosePull(t.Context(), pull); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
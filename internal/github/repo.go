@@ -9,28 +9,38 @@ import (
 	"github.com/nobe4/action-ln/internal/log"
 )
 
+// ForgeGitLab hosts the repos with Repo.Forge set to it.
+const ForgeGitLab = "gitlab"
+
 type Repo struct {
 	Owner         User   `json:"owner"`
 	Repo          string `json:"repo"`
 	DefaultBranch string `json:"default_branch"`
+
+	// Forge hosts the repo, GitHub if empty.
+	Forge string `json:"forge,omitempty"`
 }
 
 var errGetRepo = errors.New("failed to get repo")
 
 func (r Repo) Equal(o Repo) bool {
-	return r.Repo == o.Repo && r.Owner.Login == o.Owner.Login
+	return r.Repo == o.Repo && r.Owner.Login == o.Owner.Login && r.Forge == o.Forge
 }
 
 func (r Repo) Empty() bool {
-	return r.Repo == "" && r.Owner.Login == "" && r.DefaultBranch == ""
+	return r.Repo == "" && r.Owner.Login == "" && r.DefaultBranch == "" && r.Forge == ""
 }
 
 func (r Repo) String() string {
+	if r.Forge != "" {
+		return fmt.Sprintf("%s:%s/%s", r.Forge, r.Owner.Login, r.Repo)
+	}
+
 	return fmt.Sprintf("%s/%s", r.Owner.Login, r.Repo)
 }
 
 func (r Repo) APIPath() string {
-	return fmt.Sprintf("/repos/%s", r)
+	return fmt.Sprintf("/repos/%s/%s", r.Owner.Login, r.Repo)
 }
 
 // https://docs.github.com/en/rest/repos/repos?apiVersion=2022-11-28
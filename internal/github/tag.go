@@ -0,0 +1,64 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const tagsPerPage = 100
+
+var (
+	ErrGetTags    = errors.New("failed to get tags")
+	ErrGetRelease = errors.New("failed to get release")
+)
+
+type Tag struct {
+	Name   string `json:"name"`
+	Commit Commit `json:"commit"`
+}
+
+type Release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// https://docs.github.com/en/rest/repos/repos?apiVersion=2022-11-28#list-repository-tags
+func (g *GitHub) GetTags(ctx context.Context, r Repo) ([]Tag, error) {
+	tags := []Tag{}
+
+	for page := 1; ; page++ {
+		q := url.Values{
+			"per_page": []string{strconv.Itoa(tagsPerPage)},
+			"page":     []string{strconv.Itoa(page)},
+		}
+
+		pageTags := []Tag{}
+
+		path := fmt.Sprintf("/repos/%s/tags?%s", r, q.Encode())
+		if _, err := g.req(ctx, http.MethodGet, path, nil, &pageTags); err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", ErrGetTags, r, err)
+		}
+
+		tags = append(tags, pageTags...)
+
+		if len(pageTags) < tagsPerPage {
+			return tags, nil
+		}
+	}
+}
+
+// https://docs.github.com/en/rest/releases/releases?apiVersion=2022-11-28#get-the-latest-release
+func (g *GitHub) GetLatestRelease(ctx context.Context, r Repo) (Release, error) {
+	release := Release{}
+
+	path := fmt.Sprintf("/repos/%s/releases/latest", r)
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &release); err != nil {
+		return Release{}, fmt.Errorf("%w for %s: %w", ErrGetRelease, r, err)
+	}
+
+	return release, nil
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetTags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.GetTags(t.Context(), repo); !errors.Is(err, ErrGetTags) {
+			t.Fatalf("expected error %v, got %v", ErrGetTags, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/tags", nil)
+
+			fmt.Fprintf(w, `[{"name": "v1.0.0", "commit": {"sha": "%s"}}]`, sha)
+		})
+
+		got, err := g.GetTags(t.Context(), repo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != 1 || got[0].Name != "v1.0.0" || got[0].Commit.SHA != sha {
+			t.Fatalf("expected the tag, got %+v", got)
+		}
+	})
+
+	t.Run("gets all the pages", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") != "1" {
+				fmt.Fprint(w, `[]`)
+
+				return
+			}
+
+			tags := make([]string, tagsPerPage)
+			for i := range tags {
+				tags[i] = fmt.Sprintf(`{"name": "v%d.0.0"}`, i)
+			}
+
+			fmt.Fprintf(w, "[%s]", strings.Join(tags, ","))
+		})
+
+		got, err := g.GetTags(t.Context(), repo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(got) != tagsPerPage {
+			t.Fatalf("expected %d tags, got %d", tagsPerPage, len(got))
+		}
+	})
+}
+
+func TestGetLatestRelease(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.GetLatestRelease(t.Context(), repo); !errors.Is(err, ErrGetRelease) {
+			t.Fatalf("expected error %v, got %v", ErrGetRelease, err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, "/repos/owner/repo/releases/latest", nil)
+
+			fmt.Fprint(w, `{"tag_name": "v1.2.3"}`)
+		})
+
+		got, err := g.GetLatestRelease(t.Context(), repo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.TagName != "v1.2.3" {
+			t.Fatalf("expected tag 'v1.2.3', got %q", got.TagName)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	TypeBlob = "blob"
+	TypeTree = "tree"
+
+	// HEAD resolves to the default branch of a repository.
+	headRef = "HEAD"
+)
+
+var ErrGetTree = errors.New("failed to get tree")
+
+type TreeEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+	Size int    `json:"size"`
+}
+
+type Tree struct {
+	SHA       string      `json:"sha"`
+	Entries   []TreeEntry `json:"tree"`
+	Truncated bool        `json:"truncated"`
+}
+
+// Blobs returns the paths of all the files in the tree.
+func (t Tree) Blobs() []string {
+	paths := []string{}
+
+	for _, e := range t.Entries {
+		if e.Type == TypeBlob {
+			paths = append(paths, e.Path)
+		}
+	}
+
+	return paths
+}
+
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree
+func (g *GitHub) GetTree(ctx context.Context, r Repo, ref string) (Tree, error) {
+	log.Debug("Get tree", "repo", r, "ref", ref)
+
+	if ref == "" {
+		ref = headRef
+	}
+
+	t := Tree{}
+	path := fmt.Sprintf("/repos/%s/git/trees/%s?recursive=1", r, ref)
+
+	if _, err := g.req(ctx, http.MethodGet, path, nil, &t); err != nil {
+		return Tree{}, fmt.Errorf("%w: %w", ErrGetTree, err)
+	}
+
+	if t.Truncated {
+		log.Warn("Tree is truncated, some files might be missing", "repo", r, "ref", ref)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+const treeAPIPath = "/repos/owner/repo/git/trees/"
+
+func TestGetTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		if _, err := g.GetTree(t.Context(), repo, branch); !errors.Is(err, ErrGetTree) {
+			t.Fatalf("expected error %v, got %v", ErrGetTree, err)
+		}
+	})
+
+	t.Run("uses HEAD by default", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, treeAPIPath+"HEAD", nil)
+
+			fmt.Fprint(w, `{"sha": "sha"}`)
+		})
+
+		if _, err := g.GetTree(t.Context(), repo, ""); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		g := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assertReq(t, r, http.MethodGet, treeAPIPath+branch, nil)
+
+			if rec := r.URL.Query().Get("recursive"); rec != "1" {
+				t.Fatalf("expected recursive to be '1' but got '%s'", rec)
+			}
+
+			fmt.Fprint(w, `{
+				"sha": "sha",
+				"tree": [
+					{ "path": "a", "type": "tree" },
+					{ "path": "a/b.txt", "type": "blob" },
+					{ "path": "c.txt", "type": "blob" }
+				]
+			}`)
+		})
+
+		got, err := g.GetTree(t.Context(), repo, branch)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []string{"a/b.txt", "c.txt"}
+		if blobs := got.Blobs(); !slices.Equal(blobs, want) {
+			t.Fatalf("want blobs %v, got %v", want, blobs)
+		}
+	})
+}
@@ -12,54 +12,76 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/nobe4/action-ln/internal/backend"
 	"github.com/nobe4/action-ln/internal/config"
 	"github.com/nobe4/action-ln/internal/environment"
+	"github.com/nobe4/action-ln/internal/format"
 	contextfmt "github.com/nobe4/action-ln/internal/format/context"
 	"github.com/nobe4/action-ln/internal/github"
 	"github.com/nobe4/action-ln/internal/log"
 )
 
-func Run(ctx context.Context, e environment.Environment, g *github.GitHub) error {
-	c, err := getConfig(ctx, g, e)
+func Run(ctx context.Context, e environment.Environment, g backend.Backend) error {
+	c, f, err := getConfig(ctx, g, e)
 	if err != nil {
 		return err
 	}
 
-	f := contextfmt.New(c, e)
+	if e.Mode == environment.ModeReport {
+		return report(ctx, g, e, c.Links)
+	}
 
 	groups := c.Links.Groups()
 
 	log.Debug("Processing groups", "groups", "\n"+groups.String())
 
-	if err := processGroups(ctx, g, f, groups); err != nil {
+	p := newPreview(e.Noop)
+
+	if err := processGroups(ctx, g, f, p, groups); err != nil {
 		return fmt.Errorf("failed to process the groups: %w", err)
 	}
 
-	return nil
+	return p.write(e.NoopOutput)
 }
 
-func getConfig(ctx context.Context, g *github.GitHub, e environment.Environment) (*config.Config, error) {
+func getConfig(ctx context.Context, g backend.Backend, e environment.Environment) (
+	*config.Config,
+	format.Formatter,
+	error,
+) {
 	source, err := readConfig(ctx, g, e)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
 	c := config.New(source, e.Repo)
+	f := contextfmt.New(c, e)
+
+	if err := c.Parse(ctx, strings.NewReader(source.Content), g); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config %#v: %w", source, err)
+	}
+
+	// NOTE: The links need to be formatted before being populated, as their
+	// branch is used to get the `to` file.
+	if err := c.Links.Format(f); err != nil {
+		return nil, nil, fmt.Errorf("failed to format links: %w", err)
+	}
 
-	if err := c.Parse(strings.NewReader(source.Content)); err != nil {
-		return nil, fmt.Errorf("failed to parse config %#v: %w", source, err)
+	// NOTE: The groups are only known once the branches are formatted.
+	if err := c.Links.Groups().Validate(); err != nil {
+		return nil, nil, fmt.Errorf("failed to validate links: %w", err)
 	}
 
 	if err := c.Populate(ctx, g); err != nil {
-		return nil, fmt.Errorf("failed to populate config: %w", err)
+		return nil, nil, fmt.Errorf("failed to populate config: %w", err)
 	}
 
 	log.Debug("Parsed config", "config", c)
 
-	return c, nil
+	return c, f, nil
 }
 
-func readConfig(ctx context.Context, g *github.GitHub, e environment.Environment) (github.File, error) {
+func readConfig(ctx context.Context, g backend.Backend, e environment.Environment) (github.File, error) {
 	log.Group("Read config")
 	defer log.GroupEnd()
 
@@ -70,7 +92,7 @@ func readConfig(ctx context.Context, g *github.GitHub, e environment.Environment
 	return readConfigFromFS(e.LocalConfig)
 }
 
-func readConfigFromGitHub(ctx context.Context, g *github.GitHub, e environment.Environment) (github.File, error) {
+func readConfigFromGitHub(ctx context.Context, g backend.Backend, e environment.Environment) (github.File, error) {
 	log.Info("Read config from GitHub", "repo", e.Repo)
 
 	b, err := g.GetDefaultBranch(ctx, e.Repo)
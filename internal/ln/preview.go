@@ -0,0 +1,74 @@
+package ln
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/config"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+// preview collects, in noop mode, what each group would change: the diff of
+// each link and the pull request.
+// A nil preview collects nothing.
+type preview struct {
+	out strings.Builder
+}
+
+// newPreview returns a preview in noop mode, nil otherwise.
+func newPreview(noop bool) *preview {
+	if !noop {
+		return nil
+	}
+
+	return &preview{}
+}
+
+// add previews the links that would be written, compared to their `to` at ref.
+func (p *preview) add(ctx context.Context, g backend.Backend, l config.Links, ref, title, body string) {
+	if p == nil {
+		return
+	}
+
+	section := strings.Builder{}
+
+	fmt.Fprintf(&section, "# `%s@%s`\n\n", l[0].To.Repo, l[0].Branch)
+	fmt.Fprintf(&section, "## Pull request: %s\n\n%s\n\n## Changes\n", title, strings.TrimSpace(body))
+
+	for _, link := range l {
+		if link.Status != config.StatusUpdated && link.Status != config.StatusDeleted {
+			continue
+		}
+
+		d, err := link.Drift(ctx, g, ref)
+		if err != nil {
+			log.Warn("Failed to preview", "link", link, "err", err)
+
+			continue
+		}
+
+		fmt.Fprintf(&section, "\n### `%s`\n\n%s", d.To, d.MarkdownDiff())
+	}
+
+	log.Notice("[NOOP] Preview\n" + section.String())
+
+	p.out.WriteString(section.String() + "\n")
+}
+
+// write saves the preview to path, if set.
+func (p *preview) write(path string) error {
+	if p == nil || path == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(p.out.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write the preview: %w", err)
+	}
+
+	log.Info("Wrote the preview", "path", path)
+
+	return nil
+}
@@ -2,8 +2,10 @@ package ln
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/nobe4/action-ln/internal/backend"
 	"github.com/nobe4/action-ln/internal/config"
 	"github.com/nobe4/action-ln/internal/format"
 	"github.com/nobe4/action-ln/internal/github"
@@ -11,29 +13,21 @@ import (
 )
 
 const (
-	headName         = "auto-action-ln"
-	pullTitle        = "auto(ln): update links"
-	pullBodyTemplate = `
-{{/* This defines a backtick character to use in the markdown. */}}
-{{- $b := "` + "`" + `" -}}
-This automated PR updates the following files:
-
-| From | To  | Status |
-| ---  | --- | ---    |
-{{ range .Data -}}
-| [{{ $b }}{{ .From }}{{ $b }}]({{ .From.HTMLURL }}) | {{ $b }}{{ .To.Path }}{{ $b }} | {{ .Status }} |
-{{ end }}
-
----
-
-| Quick links | [execution]({{ .Environment.ExecURL }}) | [configuration]({{ .Environment.Server }}{{ .Config.Source.HTMLPath }}) | [action-ln](https://github.com/nobe4/action-ln) |
-| --- | --- | --- | --- |
-`
+	staleComment        = "All the links are already in sync with `%s`, this pull request is not needed anymore."
+	mergedComment       = "Merged with the `%s` method."
+	autoMergeComment    = "Enabled auto-merge with the `%s` method, it merges once all the requirements are met."
+	notMergeableComment = "This pull request cannot be merged yet, it is retried on the next run: %s"
 )
 
-func processGroups(ctx context.Context, g *github.GitHub, f format.Formatter, groups config.Groups) error {
+func processGroups(
+	ctx context.Context,
+	g backend.Backend,
+	f format.Formatter,
+	p *preview,
+	groups config.Groups,
+) error {
 	for _, l := range groups {
-		if err := processLinks(ctx, g, f, l); err != nil {
+		if err := processLinks(ctx, g, f, p, l); err != nil {
 			return err
 		}
 	}
@@ -41,10 +35,11 @@ func processGroups(ctx context.Context, g *github.GitHub, f format.Formatter, gr
 	return nil
 }
 
-func processLinks(ctx context.Context, g *github.GitHub, f format.Formatter, l config.Links) error {
+func processLinks(ctx context.Context, g backend.Backend, f format.Formatter, p *preview, l config.Links) error {
 	toRepo := l[0].To.Repo
+	headName := l[0].Branch
 
-	log.Group("Processing links for " + toRepo.String())
+	log.Group("Processing links for " + toRepo.String() + "@" + headName)
 	defer log.GroupEnd()
 
 	base, head, err := g.GetBaseAndHeadBranches(ctx, toRepo, headName)
@@ -54,7 +49,15 @@ func processLinks(ctx context.Context, g *github.GitHub, f format.Formatter, l c
 
 	log.Debug("Parsed branches", "head", head, "base", base)
 
-	updated := l.Update(ctx, g, f, head)
+	target := head
+
+	if l[0].ShouldRebase() {
+		if target, err = rebase(ctx, g, l, base, head); err != nil {
+			return fmt.Errorf("failed to rebase: %w", err)
+		}
+	}
+
+	updated := l.Update(ctx, g, f, target)
 	if !updated && head.New {
 		log.Info("No link was updated, cleaning up.", "repo", toRepo, "branch", head.Name)
 
@@ -65,19 +68,250 @@ func processLinks(ctx context.Context, g *github.GitHub, f format.Formatter, l c
 		return nil
 	}
 
-	pullBody, err := f.Format(pullBodyTemplate, l)
+	if !updated {
+		closed, err := closeStale(ctx, g, l, base, head)
+		if err != nil {
+			return fmt.Errorf("failed to close stale pull request: %w", err)
+		}
+
+		if closed {
+			return nil
+		}
+	}
+
+	// NOTE: The history is also needed for the links updated in a previous
+	// run, so that the body always lists all the upstream changes.
+	l.History(ctx, g)
+
+	pullTitle, err := f.FormatText(l[0].Pull.Title, l)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request title: %w", err)
+	}
+
+	pullBody, err := f.Format(l[0].Pull.Body, l)
 	if err != nil {
 		return fmt.Errorf("failed to create pull request body: %w", err)
 	}
 
 	log.Debug("Pull body", "body", pullBody)
 
-	pull, err := g.GetOrCreatePull(ctx, toRepo, base.Name, head.Name, pullTitle, pullBody)
+	// NOTE: In noop mode, a new head branch is not really created, so the
+	// links are compared to base, where it would start from.
+	previewRef := target.Name
+	if target.New {
+		previewRef = base.Name
+	}
+
+	p.add(ctx, g, l, previewRef, pullTitle, pullBody)
+
+	pullConfig := l[0].Pull
+
+	pull, err := g.GetOrCreatePull(ctx, toRepo, base.Name, head.Name, pullTitle, pullBody, pullConfig.IsDraft())
 	if err != nil {
 		return fmt.Errorf("failed to get pull request: %w", err)
 	}
 
 	log.Info("Result pull request", "pull", pull, "new", pull.New)
 
+	// NOTE: The title and body are refreshed so that they always reflect the
+	// current state of the head branch.
+	if !pull.New {
+		if pull, err = g.UpdatePull(ctx, pull, pullTitle, pullBody); err != nil {
+			return fmt.Errorf("failed to update pull request: %w", err)
+		}
+
+		log.Info("Updated pull request", "pull", pull)
+
+		if pullConfig.IsDraft() && !pull.Draft {
+			if err := g.ConvertToDraft(ctx, pull); err != nil {
+				return fmt.Errorf("failed to convert pull request to draft: %w", err)
+			}
+
+			log.Info("Converted pull request to draft", "pull", pull)
+		}
+	}
+
+	if err := updatePullMetadata(ctx, g, pull, pullConfig); err != nil {
+		return fmt.Errorf("failed to update pull request metadata: %w", err)
+	}
+
+	// NOTE: The merge is only blocked for a new reason when something changed.
+	if err := mergePull(ctx, g, pull, pullConfig.Merge, updated || pull.New); err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+
+	return nil
+}
+
+// mergePull applies the merge policy, and comments the outcome on the pull
+// request.
+// A pull request that can't be merged yet, e.g. because of pending checks, is
+// not an error: it will be retried on the next run. This is only commented
+// when changed is set, so that each run doesn't add the same comment.
+func mergePull(ctx context.Context, g backend.Backend, pull github.Pull, m config.PullMerge, changed bool) error {
+	var comment string
+
+	switch m.Mode {
+	case config.MergeImmediate:
+		err := g.MergePull(ctx, pull, m.MergeMethod())
+		if errors.Is(err, github.ErrPullNotMergeable) {
+			log.Warn("Pull request cannot be merged yet", "pull", pull, "err", err)
+
+			if !changed {
+				return nil
+			}
+
+			comment = fmt.Sprintf(notMergeableComment, err)
+
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("immediate: %w", err)
+		}
+
+		log.Notice("Merged pull request", "pull", pull, "method", m.MergeMethod())
+
+		comment = fmt.Sprintf(mergedComment, m.MergeMethod())
+
+	case config.MergeAuto:
+		if pull.AutoMerge != nil {
+			log.Info("Auto-merge already enabled", "pull", pull, "method", pull.AutoMerge.MergeMethod)
+
+			return nil
+		}
+
+		if err := g.EnableAutoMerge(ctx, pull, m.MergeMethod()); err != nil {
+			return fmt.Errorf("auto: %w", err)
+		}
+
+		log.Notice("Enabled auto-merge", "pull", pull, "method", m.MergeMethod())
+
+		comment = fmt.Sprintf(autoMergeComment, m.MergeMethod())
+
+	default:
+		log.Debug("Merge disabled", "pull", pull)
+
+		return nil
+	}
+
+	if err := g.AddComment(ctx, pull, comment); err != nil {
+		return fmt.Errorf("failed to comment the merge: %w", err)
+	}
+
+	return nil
+}
+
+// rebase resets head onto base when it is behind, so that the pull request
+// never conflicts with or reverts the work done on base.
+// The links are then checked again against the reset head, to re-apply the
+// ones that were already synced.
+func rebase(
+	ctx context.Context,
+	g backend.Backend,
+	l config.Links,
+	base, head github.Branch,
+) (github.Branch, error) {
+	if head.New {
+		return head, nil
+	}
+
+	toRepo := l[0].To.Repo
+
+	c, err := g.Compare(ctx, toRepo, base.Name, head.Name)
+	if err != nil {
+		return head, err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	if !c.Behind() {
+		log.Debug("Head is up to date with base", "head", head.Name, "base", base.Name, "status", c.Status)
+
+		return head, nil
+	}
+
+	log.Info("Head is behind base, resetting it", "head", head.Name, "base", base.Name, "behind", c.BehindBy)
+
+	reset, err := g.ResetBranch(ctx, toRepo, head.Name, base.Commit.SHA)
+	if err != nil {
+		return head, err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	if err := l.RefreshTo(ctx, g, reset.Name); err != nil {
+		return head, err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	return reset, nil
+}
+
+// closeStale closes the pull request and deletes the head branch when the head
+// branch differs from base, but every link is already in sync with base.
+// E.g. when an upstream change is reverted.
+func closeStale(
+	ctx context.Context,
+	g backend.Backend,
+	l config.Links,
+	base, head github.Branch,
+) (bool, error) {
+	if head.Commit.SHA == base.Commit.SHA {
+		return false, nil
+	}
+
+	inSync, err := l.InSync(ctx, g, base.Name)
+	if err != nil || !inSync {
+		return false, err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	toRepo := l[0].To.Repo
+
+	log.Info("All links are in sync with base, cleaning up.", "repo", toRepo, "base", base.Name, "head", head.Name)
+
+	pull, err := g.GetPull(ctx, toRepo, base.Name, head.Name)
+
+	switch {
+	case errors.Is(err, github.ErrNoPull):
+		log.Debug("No pull request to close", "repo", toRepo, "head", head.Name)
+
+	case err != nil:
+		return false, fmt.Errorf("failed to get pull request: %w", err)
+
+	default:
+		comment := fmt.Sprintf(staleComment, base.Name)
+		if err := g.AddComment(ctx, pull, comment); err != nil {
+			return false, fmt.Errorf("failed to comment: %w", err)
+		}
+
+		if err := g.ClosePull(ctx, pull); err != nil {
+			return false, fmt.Errorf("failed to close: %w", err)
+		}
+
+		log.Notice("Closed stale pull request", "pull", pull)
+	}
+
+	if err := g.DeleteBranch(ctx, toRepo, head.Name); err != nil {
+		return false, fmt.Errorf("failed to delete stale branch: %w", err)
+	}
+
+	return true, nil
+}
+
+// updatePullMetadata applies the labels, assignees, reviewers, and milestone.
+// They are applied on every run, so that existing pull requests get them too.
+func updatePullMetadata(ctx context.Context, g backend.Backend, pull github.Pull, p config.Pull) error {
+	if err := g.AddLabels(ctx, pull, p.Labels); err != nil {
+		return fmt.Errorf("labels: %w", err)
+	}
+
+	if err := g.AddAssignees(ctx, pull, p.Assignees); err != nil {
+		return fmt.Errorf("assignees: %w", err)
+	}
+
+	if err := g.RequestReviewers(ctx, pull, p.Reviewers, p.TeamReviewers); err != nil {
+		return fmt.Errorf("reviewers: %w", err)
+	}
+
+	if err := g.SetMilestone(ctx, pull, p.Milestone); err != nil {
+		return fmt.Errorf("milestone: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,117 @@
+package ln
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/config"
+	"github.com/nobe4/action-ln/internal/github"
+)
+
+var errTest = errors.New("test")
+
+// merger records the comments, the other methods are not implemented.
+type merger struct {
+	backend.Backend
+
+	err      error
+	comments []string
+}
+
+func (m *merger) MergePull(context.Context, github.Pull, string) error {
+	return m.err
+}
+
+func (m *merger) EnableAutoMerge(context.Context, github.Pull, string) error {
+	return m.err
+}
+
+func (m *merger) AddComment(_ context.Context, _ github.Pull, comment string) error {
+	m.comments = append(m.comments, comment)
+
+	return nil
+}
+
+func TestMergePull(t *testing.T) {
+	t.Parallel()
+
+	notMergeable := errors.Join(github.ErrPullNotMergeable, errTest)
+	autoMerge := github.Pull{AutoMerge: &struct {
+		MergeMethod string `json:"merge_method"`
+	}{}}
+
+	tests := []struct {
+		name    string
+		mode    string
+		pull    github.Pull
+		err     error
+		changed bool
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "does nothing without merge",
+			mode: config.MergeNone,
+		},
+		{
+			name: "comments the merge",
+			mode: config.MergeImmediate,
+			want: []string{"Merged with the `squash` method."},
+		},
+		{
+			name:    "comments why it cannot be merged",
+			mode:    config.MergeImmediate,
+			err:     notMergeable,
+			changed: true,
+			want:    []string{"This pull request cannot be merged yet, it is retried on the next run: " + notMergeable.Error()},
+		},
+		{
+			name: "does not comment the same reason again",
+			mode: config.MergeImmediate,
+			err:  notMergeable,
+		},
+		{
+			name:    "fails to merge",
+			mode:    config.MergeImmediate,
+			err:     errTest,
+			changed: true,
+			wantErr: errTest,
+		},
+		{
+			name: "comments the auto-merge",
+			mode: config.MergeAuto,
+			want: []string{"Enabled auto-merge with the `squash` method, it merges once all the requirements are met."},
+		},
+		{
+			name: "does not enable the auto-merge again",
+			mode: config.MergeAuto,
+			pull: autoMerge,
+		},
+		{
+			name:    "fails to enable the auto-merge",
+			mode:    config.MergeAuto,
+			err:     errTest,
+			wantErr: errTest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &merger{err: test.err}
+			m := config.PullMerge{Mode: test.mode, Method: "squash"}
+
+			if err := mergePull(t.Context(), g, test.pull, m, test.changed); !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+
+			if !slices.Equal(test.want, g.comments) {
+				t.Fatalf("want comments %q, got %q", test.want, g.comments)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package ln
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nobe4/action-ln/internal/backend"
+	"github.com/nobe4/action-ln/internal/config"
+	"github.com/nobe4/action-ln/internal/environment"
+	"github.com/nobe4/action-ln/internal/log"
+)
+
+const (
+	reportMarkdown = "report.md"
+	reportJSON     = "report.json"
+)
+
+// report checks how each link drifted from the default branch of its `to`
+// repository, without creating any branch or pull request.
+func report(ctx context.Context, g backend.Backend, e environment.Environment, l config.Links) error {
+	log.Group("Drift report")
+	defer log.GroupEnd()
+
+	bases := map[string]string{}
+	drifts := config.Drifts{}
+
+	for _, link := range l {
+		repo := link.To.Repo.String()
+
+		base, ok := bases[repo]
+		if !ok {
+			var err error
+			if base, err = g.GetDefaultBranchName(ctx, link.To.Repo); err != nil {
+				return fmt.Errorf("failed to get the default branch of %s: %w", repo, err)
+			}
+
+			bases[repo] = base
+		}
+
+		d, err := link.Drift(ctx, g, base)
+		if err != nil {
+			log.Error("failed to check the drift", "link", link, "error", err)
+			d.Status = config.DriftFailed
+		}
+
+		log.Info("Checked drift", "link", link, "status", d.Status)
+
+		drifts = append(drifts, d)
+	}
+
+	markdown := drifts.Markdown()
+
+	jsonReport, err := drifts.JSON()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	log.Notice("Drift report", "summary", drifts.Summary())
+	log.Info("Drift report\n" + markdown)
+
+	if e.StepSummary != "" {
+		if err := appendFile(e.StepSummary, markdown); err != nil {
+			return fmt.Errorf("failed to write the step summary: %w", err)
+		}
+	}
+
+	if e.ReportDir != "" {
+		if err := writeReport(e.ReportDir, markdown, jsonReport); err != nil {
+			return fmt.Errorf("failed to write the report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeReport(dir, markdown, jsonReport string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, reportMarkdown), []byte(markdown), 0o600); err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, reportJSON), []byte(jsonReport), 0o600); err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	log.Info("Wrote the report", "dir", dir)
+
+	return nil
+}
+
+func appendFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		return err //nolint:wrapcheck // Wrapped by the caller.
+	}
+
+	return nil
+}
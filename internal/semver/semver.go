@@ -0,0 +1,309 @@
+/*
+Package semver implements the subset of semantic versioning needed to resolve
+version constraints against git tags.
+
+https://semver.org
+*/
+package semver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// How many numbers of a version are set.
+const (
+	majorSet = iota + 1
+	minorSet
+	patchSet
+
+	operators = "<>=!^~|,"
+)
+
+var (
+	ErrInvalidVersion    = errors.New("invalid version")
+	ErrInvalidConstraint = errors.New("invalid constraint")
+)
+
+// Version is a parsed semantic version. Build metadata is ignored.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string
+}
+
+// Parse reads a version like `v1.2.3`, `1.2` or `1.2.3-rc.1`.
+// Missing minor and patch numbers default to 0.
+func Parse(s string) (Version, error) {
+	v, _, err := parse(s, false)
+
+	return v, err
+}
+
+// parse returns the version and how many numbers were set before a wildcard.
+func parse(s string, wildcard bool) (Version, int, error) {
+	core, _, _ := strings.Cut(strings.TrimPrefix(s, "v"), "+")
+	core, pre, _ := strings.Cut(core, "-")
+
+	numbers := strings.Split(core, ".")
+	if len(numbers) > patchSet {
+		return Version{}, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+
+	values := [patchSet]int{}
+	set := 0
+
+	for i, n := range numbers {
+		if wildcard && isWildcard(n) {
+			for _, rest := range numbers[i:] {
+				if !isWildcard(rest) {
+					return Version{}, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+				}
+			}
+
+			break
+		}
+
+		value, err := strconv.Atoi(n)
+		if err != nil || value < 0 {
+			return Version{}, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+
+		values[i] = value
+		set++
+	}
+
+	return Version{Major: values[0], Minor: values[1], Patch: values[2], Pre: pre}, set, nil
+}
+
+func isWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or
+// greater than o. Pre-releases are compared as plain strings.
+func (v Version) Compare(o Version) int {
+	if c := cmp.Compare(v.Major, o.Major); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	default:
+		return strings.Compare(v.Pre, o.Pre)
+	}
+}
+
+// bump returns the lowest version above all the versions sharing the first n
+// numbers of v.
+func (v Version) bump(n int) Version {
+	switch n {
+	case majorSet:
+		return Version{Major: v.Major + 1}
+	case minorSet:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+type term struct {
+	op       string
+	version  Version
+	set      int
+	wildcard bool
+}
+
+// Constraint is a set of alternatives separated by `||`, each made of terms
+// that must all match, separated by commas or spaces.
+//
+// Terms support `=`, `!=`, `>`, `>=`, `<`, `<=`, `^` (same major), `~` (same
+// minor) and wildcards (e.g. `2.x`, `2.1.*`).
+type Constraint [][]term
+
+// ParseConstraint reads a constraint like `^2.1`, `v2.x` or `>=1.2, <2 || 3.x`.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{}
+
+	for alternative := range strings.SplitSeq(s, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, s)
+		}
+
+		terms := []term{}
+
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+
+			// Allow a space between the operator and the version, e.g. `>= 1.2`.
+			if strings.Trim(f, operators) == "" && i+1 < len(fields) {
+				i++
+				f += fields[i]
+			}
+
+			t, err := parseTerm(f)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidConstraint, s, err)
+			}
+
+			terms = append(terms, t)
+		}
+
+		c = append(c, terms)
+	}
+
+	return c, nil
+}
+
+func parseTerm(s string) (term, error) {
+	t := term{}
+
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, op) {
+			t.op = op
+			s = strings.TrimPrefix(s, op)
+
+			break
+		}
+	}
+
+	v, set, err := parse(s, true)
+	if err != nil {
+		return term{}, err
+	}
+
+	t.version = v
+	t.set = set
+	t.wildcard = set < patchSet && strings.ContainsAny(s, "xX*")
+
+	return t, nil
+}
+
+// IsConstraint reports whether s is a constraint rather than a plain version
+// or ref. E.g. `^2`, `v2.x` and `>=1.2` are, `v2`, `v1.2.3` and `main` are not.
+func IsConstraint(s string) bool {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		return false
+	}
+
+	if strings.ContainsAny(s, operators) {
+		return true
+	}
+
+	for _, terms := range c {
+		for _, t := range terms {
+			if t.wildcard {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Check reports whether v satisfies the constraint.
+// Pre-releases never do.
+func (c Constraint) Check(v Version) bool {
+	if v.Pre != "" {
+		return false
+	}
+
+	for _, terms := range c {
+		ok := true
+
+		for _, t := range terms {
+			if !t.check(v) {
+				ok = false
+
+				break
+			}
+		}
+
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+//nolint:cyclop // Each operator is simple.
+func (t term) check(v Version) bool {
+	if t.set == 0 {
+		return t.op != "!="
+	}
+
+	c := v.Compare(t.version)
+
+	switch t.op {
+	case "", "=":
+		return c >= 0 && v.Compare(t.version.bump(t.set)) < 0
+	case "!=":
+		return c < 0 || v.Compare(t.version.bump(t.set)) >= 0
+	case ">":
+		return v.Compare(t.version.bump(t.set)) >= 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return v.Compare(t.version.bump(t.set)) < 0
+	case "^":
+		return c >= 0 && v.Compare(t.version.bump(t.caretSet())) < 0
+	case "~":
+		return c >= 0 && v.Compare(t.version.bump(t.tildeSet())) < 0
+	}
+
+	return false
+}
+
+// caretSet returns how many numbers can't change for `^`: all up to the first
+// non-zero one.
+func (t term) caretSet() int {
+	switch {
+	case t.version.Major > 0 || t.set == majorSet:
+		return majorSet
+	case t.version.Minor > 0 || t.set == minorSet:
+		return minorSet
+	default:
+		return patchSet
+	}
+}
+
+// tildeSet returns how many numbers can't change for `~`: the major and minor
+// ones, or only the major one if the minor isn't set.
+func (t term) tildeSet() int {
+	if t.set == majorSet {
+		return majorSet
+	}
+
+	return minorSet
+}
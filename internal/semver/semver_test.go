@@ -0,0 +1,168 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want Version
+		err  error
+	}{
+		{s: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{s: "v1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{s: "v1.2", want: Version{Major: 1, Minor: 2}},
+		{s: "v1", want: Version{Major: 1}},
+		{s: "v1.2.3-rc.1", want: Version{Major: 1, Minor: 2, Patch: 3, Pre: "rc.1"}},
+		{s: "v1.2.3+build", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{s: "", err: ErrInvalidVersion},
+		{s: "main", err: ErrInvalidVersion},
+		{s: "v1.x", err: ErrInvalidVersion},
+		{s: "1.2.3.4", err: ErrInvalidVersion},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(test.s)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("want error %v, got %v", test.err, err)
+			}
+
+			if got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2.3", b: "v1.2.3", want: 0},
+		{a: "2.0.0", b: "1.9.9", want: 1},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "1.2.3", b: "1.2.4", want: -1},
+		{a: "1.2.3", b: "1.2.3-rc.1", want: 1},
+		{a: "1.2.3-rc.1", b: "1.2.3-rc.2", want: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" "+test.b, func(t *testing.T) {
+			t.Parallel()
+
+			a, _ := Parse(test.a)
+			b, _ := Parse(test.b)
+
+			if got := a.Compare(b); got != test.want {
+				t.Fatalf("want %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestIsConstraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "main"},
+		{s: "v2"},
+		{s: "v1.2.3"},
+		{s: "feature/x"},
+		{s: "0123456789abcdef0123456789abcdef01234567"},
+		{s: "^2", want: true},
+		{s: "~2.1", want: true},
+		{s: "v2.x", want: true},
+		{s: "2.1.*", want: true},
+		{s: ">=1.2, <2", want: true},
+		{s: "1.x || 2.x", want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsConstraint(test.s); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "||", ">=", "^main", "1.x.2"} {
+		t.Run(s, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseConstraint(s); !errors.Is(err, ErrInvalidConstraint) {
+				t.Fatalf("want error %v, got %v", ErrInvalidConstraint, err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{constraint: "*", match: []string{"0.0.1", "3.2.1"}, noMatch: []string{"1.0.0-rc.1"}},
+		{constraint: "v2.x", match: []string{"2.0.0", "2.9.9"}, noMatch: []string{"1.9.9", "3.0.0"}},
+		{constraint: "2.1.*", match: []string{"2.1.0", "2.1.9"}, noMatch: []string{"2.0.9", "2.2.0"}},
+		{constraint: "=1.2.3", match: []string{"1.2.3"}, noMatch: []string{"1.2.4"}},
+		{constraint: "!=1.2.3", match: []string{"1.2.2", "1.2.4"}, noMatch: []string{"1.2.3"}},
+		{constraint: ">1.2", match: []string{"1.3.0"}, noMatch: []string{"1.2.9"}},
+		{constraint: ">1.2.3", match: []string{"1.2.4"}, noMatch: []string{"1.2.3"}},
+		{constraint: ">= 1.2", match: []string{"1.2.0", "2.0.0"}, noMatch: []string{"1.1.9"}},
+		{constraint: "<1.2", match: []string{"1.1.9"}, noMatch: []string{"1.2.0"}},
+		{constraint: "<=1.2", match: []string{"1.2.9"}, noMatch: []string{"1.3.0"}},
+		{constraint: "^2", match: []string{"2.0.0", "2.9.9"}, noMatch: []string{"3.0.0"}},
+		{constraint: "^2.1", match: []string{"2.1.0", "2.9.9"}, noMatch: []string{"2.0.9", "3.0.0"}},
+		{constraint: "^0.2.1", match: []string{"0.2.1", "0.2.9"}, noMatch: []string{"0.3.0"}},
+		{constraint: "^0.0.1", match: []string{"0.0.1"}, noMatch: []string{"0.0.2"}},
+		{constraint: "~2", match: []string{"2.9.0"}, noMatch: []string{"3.0.0"}},
+		{constraint: "~2.1.3", match: []string{"2.1.3", "2.1.9"}, noMatch: []string{"2.1.2", "2.2.0"}},
+		{constraint: ">=1.2, <2 || 3.x", match: []string{"1.5.0", "3.1.0"}, noMatch: []string{"2.0.0", "4.0.0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := ParseConstraint(test.constraint)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			for _, s := range test.match {
+				if v, _ := Parse(s); !c.Check(v) {
+					t.Errorf("want %s to match", s)
+				}
+			}
+
+			for _, s := range test.noMatch {
+				if v, _ := Parse(s); c.Check(v) {
+					t.Errorf("want %s to not match", s)
+				}
+			}
+		})
+	}
+}
@@ -14,8 +14,7 @@ var (
 	ErrFailTemplate    = errors.New("failed to execute template")
 )
 
-// Update replaces the parameter s with its content executed as a template.
-func Update(s *string, data any) error {
+func parse(s string) (*template.Template, error) {
 	funcMap := map[string]any{
 		"pathTrimN": path.TrimN,
 	}
@@ -23,9 +22,26 @@ func Update(s *string, data any) error {
 	t, err := template.
 		New("").
 		Funcs(funcMap).
-		Parse(*s)
+		Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidTemplate, s, err)
+	}
+
+	return t, nil
+}
+
+// Validate checks that s can be used by Update.
+func Validate(s string) error {
+	_, err := parse(s)
+
+	return err
+}
+
+// Update replaces the parameter s with its content executed as a template.
+func Update(s *string, data any) error {
+	t, err := parse(*s)
 	if err != nil {
-		return fmt.Errorf("%w: %q: %w", ErrInvalidTemplate, *s, err)
+		return err
 	}
 
 	buf := strings.Builder{}
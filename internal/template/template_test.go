@@ -45,3 +45,15 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := Validate("{{ 1 + 1 }}"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("expected error %v, got %v", ErrInvalidTemplate, err)
+	}
+
+	if err := Validate(`{{ pathTrimN "a/b" 1 }}`); err != nil {
+		t.Errorf("expected no error got %v", err)
+	}
+}